@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kevin-hanselman/slide-puzzle-solver/slide_puzzle"
+)
+
+func runAnalyze(args []string) error {
+	flags := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	rows := flags.Int("rows", 0, "number of rows in the puzzle")
+	cols := flags.Int("cols", 0, "number of columns in the puzzle")
+	empty := flags.Int("empty", 0, "value representing the empty tile")
+	samples := flags.Int("samples", 5, "number of antipodal positions to print")
+	inMemory := flags.Bool("in-memory", false, "keep the search frontier in memory instead of temporary files")
+	tempDir := flags.String("temp-dir", "", "directory for temporary files (defaults to the system temporary directory)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *rows <= 0 {
+		return fmt.Errorf("-rows must be positive")
+	}
+	if *cols <= 0 {
+		return fmt.Errorf("-cols must be positive")
+	}
+	if *samples < 0 {
+		return fmt.Errorf("-samples must not be negative")
+	}
+
+	goal, err := slide_puzzle.Goal(*rows, *cols, *empty)
+	if err != nil {
+		return err
+	}
+	result, err := slide_puzzle.EnumerateStates(*goal, slide_puzzle.EnumerateOptions{
+		InMemory:  *inMemory,
+		TempDir:   *tempDir,
+		Antipodes: *samples,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%-8s  %s\n", "Distance", "States")
+	for distance, count := range result.Counts {
+		fmt.Printf("%-8d  %d\n", distance, count)
+	}
+	fmt.Printf("Total states: %d\n", result.Total())
+	fmt.Printf("God's number: %d\n", result.MaxDistance())
+
+	if len(result.Antipodes) > 0 {
+		fmt.Printf("Antipodal positions (%d of %d):\n", len(result.Antipodes), result.Counts[result.MaxDistance()])
+		for _, p := range result.Antipodes {
+			values := make([]string, 0, (*rows)*(*cols))
+			for _, v := range p.Values() {
+				values = append(values, strconv.Itoa(v))
+			}
+			fmt.Println(strings.Join(values, " "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/kevin-hanselman/slide-puzzle-solver/slide_puzzle"
+)
+
+func runGenerate(args []string) error {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	rows := flags.Int("rows", 0, "number of rows in the puzzle")
+	cols := flags.Int("cols", 0, "number of columns in the puzzle")
+	empty := flags.Int("empty", 0, "value representing the empty tile")
+	moves := flags.Int("moves", 100, "number of random moves to make from the goal state")
+	tierName := flags.String("tier", "", "generate puzzles of this difficulty instead of making -moves random moves: easy, medium, hard or expert")
+	distance := flags.Int("distance", -1, "generate puzzles whose optimal solution takes exactly this many moves, instead of making -moves random moves")
+	count := flags.Int("count", 1, "number of puzzles to generate, one per line")
+	unique := flags.Bool("unique", false, "never print two puzzles that are the same, mirror images or rotations of each other")
+	seed := flags.Uint64("seed", 0, "random seed (0 picks one at random)")
+	render := flags.String("render", "", "also draw the puzzle to a PNG (.png) or GIF (.gif) file")
+	picture := flags.String("picture", "", "PNG or JPEG image to draw the tiles with when using -render")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *rows <= 0 {
+		return fmt.Errorf("-rows must be positive")
+	}
+	if *cols <= 0 {
+		return fmt.Errorf("-cols must be positive")
+	}
+	if *moves < 0 {
+		return fmt.Errorf("-moves must not be negative")
+	}
+	if *count <= 0 {
+		return fmt.Errorf("-count must be positive")
+	}
+	if *render != "" && *count != 1 {
+		return fmt.Errorf("-render can only be used with -count 1")
+	}
+	if *tierName != "" && *distance >= 0 {
+		return fmt.Errorf("-tier and -distance cannot be used together")
+	}
+	var tier slide_puzzle.Tier
+	if *tierName != "" {
+		var err error
+		if tier, err = slide_puzzle.ParseTier(*tierName); err != nil {
+			return err
+		}
+	}
+	if *seed == 0 {
+		*seed = rand.Uint64()
+	}
+
+	rng := rand.New(rand.NewPCG(*seed, *seed))
+	// Give up on -unique after this many duplicates in a row, since small
+	// boards or few moves may not have enough distinct puzzles.
+	const maxDuplicates = 1000
+	seen := map[string]bool{}
+	var puzzle *slide_puzzle.Puzzle
+	for generated, duplicates := 0, 0; generated < *count; {
+		var err error
+		var difficulty slide_puzzle.Difficulty
+		switch {
+		case *tierName != "":
+			puzzle, difficulty, err = slide_puzzle.ScrambleTier(*rows, *cols, *empty, tier, rng)
+		case *distance >= 0:
+			puzzle, err = slide_puzzle.ScrambleDistance(*rows, *cols, *empty, *distance, rng)
+		default:
+			puzzle, err = slide_puzzle.Scramble(*rows, *cols, *empty, *moves, rng)
+		}
+		if err != nil {
+			return err
+		}
+		if *unique {
+			key := puzzle.Canonical().String()
+			if seen[key] {
+				if duplicates++; duplicates == maxDuplicates {
+					return fmt.Errorf("only found %d unique puzzles", generated)
+				}
+				continue
+			}
+			seen[key] = true
+			duplicates = 0
+		}
+		generated++
+
+		// Print the values in a form that can be passed straight to solve.
+		values := make([]string, 0, (*rows)*(*cols))
+		for _, v := range puzzle.Values() {
+			values = append(values, strconv.Itoa(v))
+		}
+		fmt.Println(strings.Join(values, " "))
+		if *tierName != "" {
+			// Keep stdout in the form solve accepts.
+			fmt.Fprintln(os.Stderr, describeDifficulty(difficulty))
+		}
+	}
+
+	if *render != "" {
+		return renderSolution(*render, *puzzle, nil, 0, *picture)
+	}
+	return nil
+}
@@ -1,81 +1,441 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/kevin-hanselman/slide-puzzle-solver/slide_puzzle"
 )
 
 func main() {
-	rows := flag.Int("rows", 0, "number of rows in the puzzle")
-	cols := flag.Int("cols", 0, "number of columns in the puzzle")
-	empty := flag.Int("empty", 0, "value representing the empty tile")
-	flag.Parse()
-
-	// Validate flags
-	if *rows <= 0 {
-		fmt.Fprintf(os.Stderr, "Error: -rows must be positive\n")
-		os.Exit(1)
+	// Solving is the default when no subcommand is given.
+	args := os.Args[1:]
+	run := runSolve
+	if len(args) > 0 {
+		switch args[0] {
+		case "solve":
+			args = args[1:]
+		case "generate":
+			run, args = runGenerate, args[1:]
+		case "analyze":
+			run, args = runAnalyze, args[1:]
+		case "table":
+			run, args = runTable, args[1:]
+		case "rate":
+			run, args = runRate, args[1:]
+		case "replay":
+			run, args = runReplay, args[1:]
+		}
 	}
-	if *cols <= 0 {
-		fmt.Fprintf(os.Stderr, "Error: -cols must be positive\n")
+
+	if err := run(args); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
 		os.Exit(1)
 	}
+}
 
-	// Parse remaining arguments as puzzle values
-	args := flag.Args()
-	expectedArgs := (*rows) * (*cols)
-	if len(args) != expectedArgs {
-		fmt.Fprintf(os.Stderr, "Error: expected %d values for %dx%d puzzle, got %d\n", expectedArgs, *rows, *cols, len(args))
-		os.Exit(1)
+func runSolve(args []string) error {
+	flags := flag.NewFlagSet("solve", flag.ContinueOnError)
+	rows := flags.Int("rows", 0, "number of rows in the puzzle")
+	cols := flags.Int("cols", 0, "number of columns in the puzzle")
+	boardShape := flags.String("board", "grid", "shape of the board: grid, torus (edges wrap around), hex or triangle (-rows only); other shapes only support -rows, -cols, -empty and -solver bfs or astar")
+	file := flags.String("file", "", "read the puzzle from a file instead of the arguments, one row per line; -rows and -cols are not needed")
+	goalColors := flags.String("goal", "", "comma-separated colors each cell should end up with, in row-major order, or * for cells that can hold anything; the values and -empty are then colors, which may repeat")
+	emptyValues := flags.String("empty", "0", "value representing the empty tile, or comma-separated values for several interchangeable empty tiles")
+	solverName := flags.String("solver", "bfs", "solver to use: bfs (optimal), ida (optimal, low memory, parallel), hda (optimal, parallel A*), astar (bounded suboptimal, see -epsilon), anytime (improves until interrupted), table (optimal, instant, see -table) or constructive (fast, for large boards)")
+	epsilon := flags.Float64("epsilon", 0, "for -solver astar, allow solutions up to (1+epsilon) times longer than optimal; for -solver anytime, the weight of the first search")
+	workers := flags.Int("workers", 0, "for -solver ida and hda, number of parallel workers (0 uses one per CPU)")
+	pattern := flags.String("pattern", "", "for -solver ida, comma-separated tiles to build a pattern database heuristic from, e.g. 1,2,3,4,5")
+	tileCosts := flags.String("costs", "", "for -solver astar, comma-separated costs of moving tiles, e.g. 3=5,7=2, to minimize total cost instead of moves (other tiles cost 1)")
+	maxSlide := flags.Int("max-slide", 1, "count sliding up to this many tiles in a line as one move (0 for any number); only with -solver bfs")
+	tablePath := flags.String("table", "", "for -solver table, lookup table file written by the table subcommand")
+	timeout := flags.Duration("timeout", 0, "for -solver anytime, stop improving after this long (0 waits until optimal or interrupted)")
+	conventionName := flags.String("convention", "tile", "what printed moves describe: the direction the tile moves (tile) or the empty space moves (blank)")
+	render := flags.String("render", "", "write the solution as an animated GIF (.gif) or a numbered PNG sequence (.png)")
+	picture := flags.String("picture", "", "PNG or JPEG image to draw the tiles with when using -render")
+	svg := flags.String("svg", "", "write a step-by-step SVG diagram of the solution")
+	svgState := flags.String("svg-state", "", "write the starting board as a single SVG image")
+	tileSize := flags.Int("tile-size", 0, "tile size in pixels for -render, -svg and -svg-state; with -picture, the image is scaled to fit (0 uses the default, or the image's own size)")
+	colors := flags.String("colors", "", "comma-separated SVG colors, e.g. tile=#eeddbb,highlight=orange; keys: background, tile, empty, highlight, text, arrow")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	convention, err := slide_puzzle.ParseConvention(*conventionName)
+	if err != nil {
+		return err
 	}
 
-	// Convert string arguments to integers
-	values := make([]int, len(args))
-	for i, arg := range args {
-		val, err := strconv.Atoi(arg)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: invalid value '%s': %v\n", arg, err)
-			os.Exit(1)
+	if *boardShape != "grid" {
+		// Reading files, move conventions, colors and drawing are only
+		// implemented for grids.
+		supported := map[string]bool{"board": true, "rows": true, "cols": true, "empty": true, "solver": true}
+		var unsupported []string
+		flags.Visit(func(f *flag.Flag) {
+			if !supported[f.Name] {
+				unsupported = append(unsupported, "-"+f.Name)
+			}
+		})
+		if len(unsupported) > 0 {
+			return fmt.Errorf("-board %s does not support %s", *boardShape, strings.Join(unsupported, ", "))
 		}
-		values[i] = val
+		return solveGraph(*boardShape, *rows, *cols, *emptyValues, *solverName, flags.Args())
 	}
 
-	// Build grid in row-major order
-	grid := make([][]int, *rows)
-	idx := 0
-	for r := range *rows {
-		grid[r] = make([]int, *cols)
-		for c := range *cols {
-			grid[r][c] = values[idx]
-			idx++
-		}
+	grid, err := readGrid(*file, *rows, *cols, flags.Args())
+	if err != nil {
+		return err
 	}
 
+	empties, err := parseInts(*emptyValues)
+	if err != nil {
+		return fmt.Errorf("invalid -empty: %v", err)
+	}
+	empty := empties[0]
+
 	// Create puzzle
-	puzzle, err := slide_puzzle.NewPuzzle(grid, *empty)
+	var puzzle *slide_puzzle.Puzzle
+	if *goalColors != "" {
+		if len(empties) > 1 {
+			return fmt.Errorf("-goal does not support several empty tiles")
+		}
+		var goal [][]int
+		if goal, err = parseGrid(len(grid), len(grid[0]), strings.Split(*goalColors, ",")); err != nil {
+			return fmt.Errorf("invalid -goal: %v", err)
+		}
+		puzzle, err = slide_puzzle.NewColoredPuzzle(grid, goal, empty)
+	} else {
+		puzzle, err = slide_puzzle.NewPuzzleWithHoles(grid, empties)
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		return err
+	}
+
+	if *svgState != "" {
+		if err := writeStateSVG(*svgState, *puzzle, *tileSize, *colors); err != nil {
+			return err
+		}
+	}
+
+	if len(empties) > 1 {
+		if *solverName != "bfs" || *maxSlide != 1 || *render != "" || *svg != "" {
+			return fmt.Errorf("puzzles with several empty tiles only support -solver bfs, without -max-slide, -render or -svg")
+		}
+		slides, err := puzzle.SolveHoles()
+		if err != nil {
+			return err
+		}
+		steps := make([]string, len(slides))
+		for i, s := range slides {
+			steps[i] = s.Format(convention)
+		}
+		printSolution(steps)
+		return nil
+	}
+
+	if *tileCosts != "" && *solverName != "astar" {
+		return fmt.Errorf("-costs is only supported with -solver astar")
+	}
+	if *maxSlide != 1 && *solverName != "bfs" {
+		return fmt.Errorf("-max-slide is only supported with -solver bfs")
 	}
 
 	// Solve puzzle
-	moves, err := puzzle.Solve()
+	var moves []slide_puzzle.Move
+	// steps is what is printed as the solution, if not moves.
+	var steps []slide_puzzle.MultiMove
+	var notes []string
+	switch *solverName {
+	case "bfs":
+		if *maxSlide == 1 {
+			moves, err = puzzle.Solve()
+			break
+		}
+		steps, err = puzzle.SolveMultiTile(*maxSlide)
+		moves = slide_puzzle.ExpandMultiMoves(steps)
+		notes = append(notes, fmt.Sprintf("%d tiles moved in total.", len(moves)))
+	case "constructive":
+		moves, err = puzzle.SolveConstructive()
+	case "ida":
+		if *pattern == "" {
+			moves, err = puzzle.SolveIDAStar(*workers)
+			break
+		}
+		var db *slide_puzzle.PatternDatabase
+		db, err = buildPatternDatabase(len(grid), len(grid[0]), empty, *pattern)
+		if err == nil {
+			moves, err = puzzle.SolveIDAStarPDB(db, *workers)
+		}
+	case "hda":
+		moves, err = puzzle.SolveHDAStar(*workers)
+	case "table":
+		if *tablePath == "" {
+			return fmt.Errorf("-solver table requires -table")
+		}
+		var table *slide_puzzle.LookupTable
+		table, err = loadTable(*tablePath)
+		if err == nil {
+			moves, err = table.Solve(*puzzle)
+		}
+	case "astar":
+		if *tileCosts != "" {
+			var costs slide_puzzle.TileCosts
+			costs, err = parseCosts(*tileCosts)
+			if err != nil {
+				return err
+			}
+			var solution slide_puzzle.CostSolution
+			solution, err = puzzle.SolveCost(costs, *epsilon)
+			moves = solution.Moves
+			notes = append(notes, fmt.Sprintf(
+				"Total cost %d, proven within %.3gx of optimal (optimal solution costs at least %d).",
+				solution.Cost, solution.Suboptimality, solution.LowerBound,
+			))
+			break
+		}
+		var solution slide_puzzle.BoundedSolution
+		solution, err = puzzle.SolveBounded(*epsilon)
+		moves = solution.Moves
+		notes = append(notes, fmt.Sprintf(
+			"Proven within %.3gx of optimal (optimal solution has at least %d moves).",
+			solution.Suboptimality, solution.LowerBound,
+		))
+	case "anytime":
+		var solution slide_puzzle.AnytimeSolution
+		solution, err = solveAnytime(*puzzle, *epsilon, *timeout)
+		moves = solution.Moves
+		if solution.Optimal {
+			notes = append(notes, "Proven optimal.")
+		} else {
+			notes = append(notes, fmt.Sprintf("Optimal solution has at least %d moves.", solution.LowerBound))
+		}
+	default:
+		return fmt.Errorf("unknown solver %q", *solverName)
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		return err
 	}
 
 	// Print solution
-	if len(moves) == 0 {
-		fmt.Println("Puzzle is already solved!")
+	if steps != nil {
+		for i := range steps {
+			steps[i].Dir = steps[i].Dir.Convert(slide_puzzle.TileMoves, convention)
+		}
+		printSolution(steps)
 	} else {
-		fmt.Printf("Solution in %d moves:\n", len(moves))
-		for i, move := range moves {
-			fmt.Printf("%d. %s\n", i+1, move)
+		printSolution(slide_puzzle.ConvertMoves(moves, slide_puzzle.TileMoves, convention))
+	}
+	for _, note := range notes {
+		fmt.Println(note)
+	}
+
+	if *render != "" {
+		if err := renderSolution(*render, *puzzle, moves, *tileSize, *picture); err != nil {
+			return err
+		}
+	}
+
+	if *svg != "" {
+		if err := writeSVG(*svg, *puzzle, moves, *tileSize, *colors, convention); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printSolution prints the numbered steps of a solution.
+func printSolution[T any](steps []T) {
+	if len(steps) == 0 {
+		fmt.Println("Puzzle is already solved!")
+		return
+	}
+	fmt.Printf("Solution in %d moves:\n", len(steps))
+	for i, step := range steps {
+		fmt.Printf("%d. %v\n", i+1, step)
+	}
+}
+
+// solveGraph solves a puzzle on a board that is not a rectangular grid. The
+// values are given in the order the board numbers its cells.
+func solveGraph(shape string, rows, cols int, emptyValue, solverName string, args []string) error {
+	var board *slide_puzzle.Board
+	var err error
+	switch shape {
+	case "torus":
+		board, err = slide_puzzle.TorusBoard(rows, cols)
+	case "hex":
+		board, err = slide_puzzle.HexBoard(rows, cols)
+	case "triangle":
+		board, err = slide_puzzle.TriangleBoard(rows)
+	default:
+		return fmt.Errorf("unknown board shape: %s", shape)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(args) != board.Size() {
+		return fmt.Errorf("expected %d values for %s board, got %d", board.Size(), shape, len(args))
+	}
+	values := make([]int, len(args))
+	for i, arg := range args {
+		if values[i], err = strconv.Atoi(arg); err != nil {
+			return fmt.Errorf("invalid value '%s': %v", arg, err)
+		}
+	}
+	empty, err := strconv.Atoi(emptyValue)
+	if err != nil {
+		return fmt.Errorf("invalid -empty: %v", err)
+	}
+	puzzle, err := slide_puzzle.NewGraphPuzzle(board, values, empty)
+	if err != nil {
+		return err
+	}
+
+	var moves []slide_puzzle.GraphMove
+	switch solverName {
+	case "bfs":
+		moves, err = puzzle.Solve()
+	case "astar":
+		moves, err = puzzle.SolveAStar()
+	default:
+		return fmt.Errorf("-board %s only supports -solver bfs and astar", shape)
+	}
+	if err != nil {
+		return err
+	}
+	printSolution(moves)
+	return nil
+}
+
+// solveAnytime runs the anytime solver until it proves its solution optimal,
+// the timeout expires or the user interrupts it, reporting each improvement.
+func solveAnytime(puzzle slide_puzzle.Puzzle, epsilon float64, timeout time.Duration) (slide_puzzle.AnytimeSolution, error) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return puzzle.SolveAnytime(ctx, epsilon, func(s slide_puzzle.AnytimeSolution) {
+		fmt.Fprintf(os.Stderr, "Found solution in %d moves (optimal has at least %d)\n", len(s.Moves), s.LowerBound)
+	})
+}
+
+// buildPatternDatabase builds a pattern database for the comma-separated tiles
+// in pattern.
+func buildPatternDatabase(rows, cols, empty int, pattern string) (*slide_puzzle.PatternDatabase, error) {
+	tiles, err := parseInts(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -pattern: %v", err)
+	}
+	return slide_puzzle.NewPatternDatabase(rows, cols, empty, tiles)
+}
+
+// parseInts parses a comma-separated list of integers.
+func parseInts(s string) ([]int, error) {
+	var values []int
+	for _, field := range strings.Split(s, ",") {
+		val, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value '%s': %v", field, err)
+		}
+		values = append(values, val)
+	}
+	return values, nil
+}
+
+// parseCosts parses comma-separated tile=cost pairs.
+func parseCosts(s string) (slide_puzzle.TileCosts, error) {
+	costs := slide_puzzle.TileCosts{}
+	for _, pair := range strings.Split(s, ",") {
+		tileStr, costStr, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid tile cost %q", pair)
+		}
+		tile, err := strconv.Atoi(strings.TrimSpace(tileStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid tile cost %q: %v", pair, err)
+		}
+		cost, err := strconv.Atoi(strings.TrimSpace(costStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid tile cost %q: %v", pair, err)
+		}
+		costs[tile] = cost
+	}
+	return costs, nil
+}
+
+// readGrid reads the puzzle grid from path if it is set, and otherwise from the
+// values given as arguments.
+func readGrid(path string, rows, cols int, args []string) ([][]int, error) {
+	if path == "" {
+		// Validate flags
+		if rows <= 0 {
+			return nil, fmt.Errorf("-rows must be positive")
+		}
+		if cols <= 0 {
+			return nil, fmt.Errorf("-cols must be positive")
+		}
+		return parseGrid(rows, cols, args)
+	}
+
+	if len(args) > 0 {
+		return nil, fmt.Errorf("puzzle values cannot be given as arguments with -file")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return slide_puzzle.ReadGrid(f)
+}
+
+// parseGrid converts puzzle values given in row-major order into a grid. A "#"
+// marks a blocked cell and a "*" a wildcard goal cell.
+func parseGrid(rows, cols int, args []string) ([][]int, error) {
+	expectedArgs := rows * cols
+	if len(args) != expectedArgs {
+		return nil, fmt.Errorf("expected %d values for %dx%d puzzle, got %d", expectedArgs, rows, cols, len(args))
+	}
+
+	// Convert string arguments to integers
+	values := make([]int, len(args))
+	for i, arg := range args {
+		switch arg {
+		case "#":
+			values[i] = slide_puzzle.Blocked
+			continue
+		case "*":
+			values[i] = slide_puzzle.Wildcard
+			continue
+		}
+		val, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value '%s': %v", arg, err)
+		}
+		values[i] = val
+	}
+
+	// Build grid in row-major order
+	grid := make([][]int, rows)
+	idx := 0
+	for r := range rows {
+		grid[r] = make([]int, cols)
+		for c := range cols {
+			grid[r][c] = values[idx]
+			idx++
 		}
 	}
+	return grid, nil
 }
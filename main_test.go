@@ -0,0 +1,33 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunSolveBoardShapes(t *testing.T) {
+	values := []string{"1", "0", "2", "3"}
+
+	t.Run("supported flags", func(t *testing.T) {
+		args := append([]string{"-board", "torus", "-rows", "2", "-cols", "2", "-solver", "astar"}, values...)
+		if err := runSolve(args); err != nil {
+			t.Errorf("runSolve(%q) error: %v", args, err)
+		}
+	})
+
+	for _, flags := range [][]string{
+		{"-file", "board.txt"},
+		{"-convention", "blank"},
+		{"-render", "solution.gif"},
+		{"-svg", "solution.svg"},
+		{"-svg-state", "board.svg"},
+	} {
+		t.Run(flags[0]+" returns error", func(t *testing.T) {
+			args := append(append([]string{"-board", "torus", "-rows", "2", "-cols", "2"}, flags...), values...)
+			err := runSolve(args)
+			if err == nil || !strings.Contains(err.Error(), flags[0]) {
+				t.Errorf("runSolve(%q) error = %v, want an error naming %s", args, err, flags[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/kevin-hanselman/slide-puzzle-solver/slide_puzzle"
+)
+
+func runRate(args []string) error {
+	flags := flag.NewFlagSet("rate", flag.ContinueOnError)
+	rows := flags.Int("rows", 0, "number of rows in the puzzle")
+	cols := flags.Int("cols", 0, "number of columns in the puzzle")
+	file := flags.String("file", "", "read the puzzle from a file instead of the arguments, one row per line; -rows and -cols are not needed")
+	empty := flags.Int("empty", 0, "value representing the empty tile")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	grid, err := readGrid(*file, *rows, *cols, flags.Args())
+	if err != nil {
+		return err
+	}
+	puzzle, err := slide_puzzle.NewPuzzle(grid, *empty)
+	if err != nil {
+		return err
+	}
+	d, err := puzzle.Rate()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Difficulty: %s (score %.1f)\n", d.Tier, d.Score)
+	if d.Exact {
+		fmt.Printf("Optimal solution: %d moves\n", d.Length)
+		fmt.Printf("Optimal solutions: %d\n", d.Solutions)
+	} else {
+		fmt.Printf("Optimal solution: at least %d moves\n", d.Length)
+	}
+	fmt.Printf("Moves beyond the Manhattan distance: %d\n", d.HeuristicGap)
+	fmt.Printf("Effective branching factor: %.2f\n", d.Branching)
+	return nil
+}
+
+// describeDifficulty summarizes a rating on one line.
+func describeDifficulty(d slide_puzzle.Difficulty) string {
+	length := fmt.Sprintf("%d moves", d.Length)
+	if !d.Exact {
+		length = fmt.Sprintf("at least %d moves", d.Length)
+	}
+	return fmt.Sprintf("%s (score %.1f, %s)", d.Tier, d.Score, length)
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kevin-hanselman/slide-puzzle-solver/slide_puzzle"
+)
+
+// renderSolution writes the solution to path as an animated GIF, or as one PNG
+// per board state named like "out-000.png", "out-001.png", etc. A single board
+// state is written to path as is. If picture is set, the tiles are drawn as
+// pieces of that image, scaled to tileSize if it is positive.
+func renderSolution(path string, puzzle slide_puzzle.Puzzle, moves []slide_puzzle.Move, tileSize int, picture string) error {
+	opts := slide_puzzle.DefaultRenderOptions()
+	if tileSize > 0 {
+		opts.TileSize = tileSize
+	}
+	if picture != "" {
+		pic, err := loadPicture(picture, puzzle.Rows(), puzzle.Cols(), tileSize)
+		if err != nil {
+			return err
+		}
+		opts.Picture = pic
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".gif":
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		if err := slide_puzzle.WriteGIF(f, puzzle, moves, opts); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	case ".png":
+		frames, err := slide_puzzle.RenderFrames(puzzle, moves, opts)
+		if err != nil {
+			return err
+		}
+		base := strings.TrimSuffix(path, filepath.Ext(path))
+		for i, frame := range frames {
+			name := fmt.Sprintf("%s-%03d.png", base, i)
+			if len(frames) == 1 {
+				// A single board is written to path itself.
+				name = path
+			}
+			f, err := os.Create(name)
+			if err != nil {
+				return err
+			}
+			if err := png.Encode(f, frame); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported render format %q; use .gif or .png", ext)
+	}
+}
+
+// writeSVG writes a step-by-step diagram of the solution to path. colors is a
+// comma-separated list of key=value pairs overriding the default colors.
+func writeSVG(path string, puzzle slide_puzzle.Puzzle, moves []slide_puzzle.Move, tileSize int, colors string, convention slide_puzzle.Convention) error {
+	opts, err := svgOptions(tileSize, colors, convention)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := slide_puzzle.WriteSolutionSVG(f, puzzle, moves, opts); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeStateSVG writes a single board to path as an SVG image. colors is as for
+// writeSVG.
+func writeStateSVG(path string, puzzle slide_puzzle.Puzzle, tileSize int, colors string) error {
+	opts, err := svgOptions(tileSize, colors, slide_puzzle.TileMoves)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := slide_puzzle.WritePuzzleSVG(f, puzzle, opts); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func svgOptions(tileSize int, colors string, convention slide_puzzle.Convention) (slide_puzzle.SVGOptions, error) {
+	opts := slide_puzzle.DefaultSVGOptions()
+	opts.Convention = convention
+	if tileSize > 0 {
+		opts.TileSize = tileSize
+	}
+
+	if colors != "" {
+		fields := map[string]*string{
+			"background": &opts.BackgroundColor,
+			"tile":       &opts.TileColor,
+			"empty":      &opts.EmptyColor,
+			"highlight":  &opts.HighlightColor,
+			"text":       &opts.TextColor,
+			"arrow":      &opts.ArrowColor,
+		}
+		for _, pair := range strings.Split(colors, ",") {
+			key, value, ok := strings.Cut(pair, "=")
+			field, known := fields[strings.TrimSpace(key)]
+			if !ok || !known {
+				return slide_puzzle.SVGOptions{}, fmt.Errorf("invalid color setting %q", pair)
+			}
+			*field = strings.TrimSpace(value)
+		}
+	}
+	return opts, nil
+}
+
+// loadPicture slices the image at path into rows x cols tiles. If tileSize is
+// positive, the image is first scaled so that each tile is tileSize pixels
+// square.
+func loadPicture(path string, rows, cols, tileSize int) (*slide_puzzle.Picture, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if tileSize <= 0 {
+		return slide_puzzle.LoadPicture(f, rows, cols)
+	}
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	return slide_puzzle.NewPicture(scaleImage(img, cols*tileSize, rows*tileSize), rows, cols)
+}
+
+// scaleImage resizes img to width x height pixels, taking the nearest pixel.
+func scaleImage(img image.Image, width, height int) image.Image {
+	bounds := img.Bounds()
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			scaled.Set(x, y, img.At(bounds.Min.X+x*bounds.Dx()/width, bounds.Min.Y+y*bounds.Dy()/height))
+		}
+	}
+	return scaled
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kevin-hanselman/slide-puzzle-solver/slide_puzzle"
+)
+
+func TestWriteStateSVG(t *testing.T) {
+	p, err := slide_puzzle.NewPuzzle([][]int{{1, 0}, {2, 3}}, 0)
+	if err != nil {
+		t.Fatalf("NewPuzzle() error: %v", err)
+	}
+
+	t.Run("writes the board", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "board.svg")
+		if err := writeStateSVG(path, *p, 30, "tile=white"); err != nil {
+			t.Fatalf("writeStateSVG() error: %v", err)
+		}
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile() error: %v", err)
+		}
+
+		opts := slide_puzzle.DefaultSVGOptions()
+		opts.TileSize = 30
+		opts.TileColor = "white"
+		var want bytes.Buffer
+		if err := slide_puzzle.WritePuzzleSVG(&want, *p, opts); err != nil {
+			t.Fatalf("WritePuzzleSVG() error: %v", err)
+		}
+		if !bytes.Equal(got, want.Bytes()) {
+			t.Errorf("writeStateSVG() wrote:\n%s\nwant:\n%s", got, want.Bytes())
+		}
+	})
+
+	t.Run("invalid colors return error", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "board.svg")
+		if err := writeStateSVG(path, *p, 0, "walls=red"); err == nil {
+			t.Errorf("writeStateSVG() error = nil for an unknown color key")
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("writeStateSVG() created %s despite the error", path)
+		}
+	})
+}
+
+func TestLoadPicture(t *testing.T) {
+	// A 4x2 image whose left half is red and right half blue.
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	blue := color.RGBA{0, 0, 0xff, 0xff}
+	for y := range 2 {
+		for x := range 4 {
+			img.Set(x, y, color.RGBA{0xff, 0, 0, 0xff})
+			if x >= 2 {
+				img.Set(x, y, blue)
+			}
+		}
+	}
+	path := filepath.Join(t.TempDir(), "picture.png")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	if err := png.Encode(f, img); err != nil {
+		t.Fatalf("png.Encode() error: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	goal, err := slide_puzzle.Goal(1, 2, 0)
+	if err != nil {
+		t.Fatalf("Goal() error: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		tileSize int
+		want     image.Rectangle
+	}{
+		{"original size", 0, image.Rect(0, 0, 4, 2)},
+		{"scaled to the tile size", 10, image.Rect(0, 0, 20, 10)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			pic, err := loadPicture(path, 1, 2, tt.tileSize)
+			if err != nil {
+				t.Fatalf("loadPicture() error: %v", err)
+			}
+			rendered, err := pic.Render(*goal)
+			if err != nil {
+				t.Fatalf("Render() error: %v", err)
+			}
+			if got := rendered.Bounds(); got != tt.want {
+				t.Errorf("Render() bounds = %v, want %v", got, tt.want)
+			}
+			corner := rendered.Bounds().Max.Sub(image.Pt(1, 1))
+			if got := rendered.RGBAAt(corner.X, corner.Y); got != blue {
+				t.Errorf("pixel at %v = %v, want %v", corner, got, blue)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/kevin-hanselman/slide-puzzle-solver/slide_puzzle"
+)
+
+func runReplay(args []string) error {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	conventionName := flags.String("convention", "tile", "what printed moves describe: the direction the tile moves (tile) or the empty space moves (blank)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	convention, err := slide_puzzle.ParseConvention(*conventionName)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("want one replay file; got %d arguments", flags.NArg())
+	}
+
+	f, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	session, err := slide_puzzle.ReadSession(f)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Started from %v\n", session.Start())
+	for i, a := range session.Actions() {
+		fmt.Printf("%d. %s %s %s\n", i+1, a.At, a.Kind, a.Move.Convert(slide_puzzle.TileMoves, convention))
+	}
+	moves := len(session.Moves())
+	if session.Solved() {
+		fmt.Printf("Solved in %d moves.\n", moves)
+	} else {
+		fmt.Printf("Not solved; %d moves made, now at %v\n", moves, session.Puzzle())
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package slide_puzzle
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math"
+)
+
+// AnytimeSolution is the best solution found so far by SolveAnytime.
+type AnytimeSolution struct {
+	Moves []Move
+	// LowerBound is a proven lower bound on the length of an optimal solution.
+	LowerBound int
+	// Optimal reports whether Moves is proven to be an optimal solution.
+	Optimal bool
+}
+
+// anytimeCheckInterval is how many nodes SolveAnytime expands between checks
+// for cancellation.
+const anytimeCheckInterval = 1024
+
+// SolveAnytime finds a first solution quickly using weighted A* with a weight
+// of 1+epsilon, then keeps searching for shorter ones until either the best
+// solution is proven optimal or ctx is cancelled. Each time a shorter solution
+// is found, it is passed to improved, which may be nil.
+//
+// SolveAnytime returns the best solution found. It only returns ctx.Err() if
+// ctx is cancelled before any solution is found.
+func (p Puzzle) SolveAnytime(ctx context.Context, epsilon float64, improved func(AnytimeSolution)) (AnytimeSolution, error) {
+	if epsilon < 0 || math.IsNaN(epsilon) || math.IsInf(epsilon, 0) {
+		return AnytimeSolution{}, fmt.Errorf("epsilon must be a non-negative number; got %v", epsilon)
+	}
+	if err := p.requireSingleEmpty(); err != nil {
+		return AnytimeSolution{}, err
+	}
+	if !p.isSolvable() {
+		return AnytimeSolution{}, UnsolvablePuzzleError{}
+	}
+	if improved == nil {
+		improved = func(AnytimeSolution) {}
+	}
+	if p.isSolved() {
+		best := AnytimeSolution{Moves: []Move{}, Optimal: true}
+		improved(best)
+		return best, nil
+	}
+
+	start := &searchNode{puzzle: p, key: p.String(), h: manhattanDistance(p)}
+	open := &nodeQueue{weight: 1 + epsilon}
+	heap.Push(open, start)
+	bestG := map[string]int{start.key: 0}
+
+	var best *AnytimeSolution
+	// bound is the length of the best solution so far. Nothing at least this
+	// long is worth exploring.
+	bound := math.MaxInt
+
+	for expanded := 0; open.Len() > 0; expanded++ {
+		if expanded%anytimeCheckInterval == 0 && ctx.Err() != nil {
+			if best == nil {
+				return AnytimeSolution{}, ctx.Err()
+			}
+			return *best, nil
+		}
+
+		current := heap.Pop(open).(*searchNode)
+		if bestG[current.key] < current.g || current.g+current.h >= bound {
+			continue
+		}
+
+		valid := current.puzzle.getMoves()
+		for _, move := range allMoves {
+			if !valid[move] {
+				continue
+			}
+			next, err := current.puzzle.makeMove(move)
+			if err != nil {
+				return AnytimeSolution{}, err
+			}
+
+			g := current.g + 1
+			h := manhattanDistance(next)
+			if g+h >= bound {
+				continue
+			}
+			key := next.String()
+			if seen, ok := bestG[key]; ok && seen <= g {
+				continue
+			}
+			bestG[key] = g
+			node := &searchNode{puzzle: next, key: key, g: g, h: h, parent: current, move: move}
+
+			if h == 0 && next.isSolved() {
+				bound = g
+				best = &AnytimeSolution{Moves: node.path()}
+				best.LowerBound = anytimeLowerBound(bound, start.h, open, bestG)
+				best.Optimal = best.LowerBound >= bound
+				improved(*best)
+				if best.Optimal {
+					return *best, nil
+				}
+				continue
+			}
+			heap.Push(open, node)
+		}
+	}
+
+	if best == nil {
+		// Blocked cells can leave solvability unknown until every reachable
+		// state has been explored.
+		return AnytimeSolution{}, UnsolvablePuzzleError{}
+	}
+	// Every state that could lead to a shorter solution has been explored.
+	best.LowerBound = bound
+	if !best.Optimal {
+		best.Optimal = true
+		improved(*best)
+	}
+	return *best, nil
+}
+
+// anytimeLowerBound returns a lower bound on the optimal solution length given
+// a solution of length bound. Any shorter solution passes through a node in
+// the open list at its optimal depth, so it is at least as long as the
+// smallest g + h there.
+func anytimeLowerBound(bound, rootH int, open *nodeQueue, bestG map[string]int) int {
+	lower := bound
+	for _, n := range open.nodes {
+		if bestG[n.key] == n.g {
+			lower = min(lower, n.g+n.h)
+		}
+	}
+	return max(lower, rootH)
+}
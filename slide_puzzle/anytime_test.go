@@ -0,0 +1,115 @@
+package slide_puzzle
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestSolveAnytime(t *testing.T) {
+	t.Run("improves to a proven optimal solution", func(t *testing.T) {
+		rng := rand.New(rand.NewPCG(5, 6))
+		for range 5 {
+			puzzle, err := Scramble(2, 4, 0, 60, rng)
+			if err != nil {
+				t.Fatalf("Scramble() error: %v", err)
+			}
+			optimal, err := puzzle.Solve()
+			if err != nil {
+				t.Fatalf("Solve() error: %v", err)
+			}
+
+			var found []AnytimeSolution
+			got, err := puzzle.SolveAnytime(context.Background(), 3, func(s AnytimeSolution) {
+				found = append(found, s)
+			})
+			if err != nil {
+				t.Fatalf("SolveAnytime() error: %v", err)
+			}
+			assertSolves(t, *puzzle, got.Moves)
+
+			if !got.Optimal || len(got.Moves) != len(optimal) || got.LowerBound != len(optimal) {
+				t.Errorf("SolveAnytime() = %d moves (optimal=%v, bound=%d), want proven optimal %d moves",
+					len(got.Moves), got.Optimal, got.LowerBound, len(optimal))
+			}
+			if len(found) == 0 {
+				t.Fatal("SolveAnytime() never reported a solution")
+			}
+			for i := 1; i < len(found); i++ {
+				if len(found[i].Moves) > len(found[i-1].Moves) {
+					t.Errorf("solution %d has %d moves, longer than previous %d", i, len(found[i].Moves), len(found[i-1].Moves))
+				}
+			}
+			if last := found[len(found)-1]; !last.Optimal {
+				t.Error("last reported solution is not marked optimal")
+			}
+		}
+	})
+
+	t.Run("cancelled before any solution", func(t *testing.T) {
+		puzzle, err := Scramble(4, 4, 0, 200, rand.New(rand.NewPCG(1, 1)))
+		if err != nil {
+			t.Fatalf("Scramble() error: %v", err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err = puzzle.SolveAnytime(ctx, 1, nil)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("SolveAnytime() error = %v, want context.Canceled", err)
+		}
+	})
+
+	t.Run("cancelled after a solution returns best so far", func(t *testing.T) {
+		puzzle, err := Scramble(4, 4, 0, 200, rand.New(rand.NewPCG(1, 1)))
+		if err != nil {
+			t.Fatalf("Scramble() error: %v", err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// Stop as soon as the first solution arrives.
+		got, err := puzzle.SolveAnytime(ctx, 4, func(AnytimeSolution) { cancel() })
+		if err != nil {
+			t.Fatalf("SolveAnytime() error: %v", err)
+		}
+		assertSolves(t, *puzzle, got.Moves)
+		if got.LowerBound > len(got.Moves) {
+			t.Errorf("lower bound %d exceeds solution length %d", got.LowerBound, len(got.Moves))
+		}
+	})
+
+	t.Run("unsolvable puzzle returns error", func(t *testing.T) {
+		grid := goalGrid(2, 3)
+		grid[1][1], grid[1][2] = grid[1][2], grid[1][1]
+		puzzle, err := NewPuzzle(grid, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		_, err = puzzle.SolveAnytime(context.Background(), 1, nil)
+		if !errors.As(err, &UnsolvablePuzzleError{}) {
+			t.Fatalf("SolveAnytime() error = %v, want UnsolvablePuzzleError", err)
+		}
+	})
+
+	t.Run("unsolvable puzzle of unknown solvability returns error", func(t *testing.T) {
+		// The tiles cannot pass the blocked cell, which wildcards leave to
+		// the search to find out.
+		blocked, err := NewPuzzle([][]int{{2, Blocked, 0}}, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		puzzle, err := blocked.PartialGoal(2)
+		if err != nil {
+			t.Fatalf("PartialGoal() error: %v", err)
+		}
+		if got := puzzle.Solvability(); got != SolvabilityUnknown {
+			t.Fatalf("Solvability() = %v, want %v", got, SolvabilityUnknown)
+		}
+		_, err = puzzle.SolveAnytime(context.Background(), 1, nil)
+		if !errors.As(err, &UnsolvablePuzzleError{}) {
+			t.Fatalf("SolveAnytime() error = %v, want UnsolvablePuzzleError", err)
+		}
+	})
+}
@@ -0,0 +1,220 @@
+package slide_puzzle
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// BoundedSolution is a solution found by a suboptimal search, along with a
+// proof of how far from optimal it can be.
+type BoundedSolution struct {
+	Moves []Move
+	// LowerBound is a proven lower bound on the length of an optimal solution.
+	LowerBound int
+	// Suboptimality is len(Moves) / LowerBound, i.e. the solution is proven
+	// to be at most this many times longer than an optimal one. It is 1 when
+	// the solution is proven optimal.
+	Suboptimality float64
+}
+
+// SolveBounded finds a solution no longer than (1+epsilon) times the optimal
+// length using weighted A* with the Manhattan distance heuristic. Larger values
+// of epsilon usually find a solution much faster; an epsilon of 0 gives an
+// optimal solution.
+//
+// The returned solution reports the bound actually proven by the search, which
+// is often much tighter than 1+epsilon.
+func (p Puzzle) SolveBounded(epsilon float64) (BoundedSolution, error) {
+	if epsilon < 0 || math.IsNaN(epsilon) || math.IsInf(epsilon, 0) {
+		return BoundedSolution{}, fmt.Errorf("epsilon must be a non-negative number; got %v", epsilon)
+	}
+	solution, err := p.weightedAStar(1+epsilon, nil)
+	return BoundedSolution{
+		Moves:         solution.Moves,
+		LowerBound:    solution.LowerBound,
+		Suboptimality: solution.Suboptimality,
+	}, err
+}
+
+// manhattanDistance returns the sum over all tiles except the empty one of the
+// distance from the tile to its goal position. Since each move changes the
+// distance of one tile by one, it never overestimates the moves remaining.
+//
+// For colored tiles, the distance to the nearest goal position of the tile's
+// color, or wildcard cell, is used instead. Tiles already in a wildcard cell
+// count for nothing, so tiles a partial goal does not constrain are ignored.
+func manhattanDistance(p Puzzle) int {
+	if p.colors != nil {
+		return coloredDistance(p)
+	}
+	cols := len(p.grid[0])
+	total := 0
+	for row := range p.grid {
+		for col, val := range p.grid[row] {
+			if val == p.emptyTile.value || val == Blocked {
+				continue
+			}
+			total += abs(row-val/cols) + abs(col-val%cols)
+		}
+	}
+	return total
+}
+
+func coloredDistance(p Puzzle) int {
+	cols := len(p.grid[0])
+	total := 0
+	for row := range p.grid {
+		for col, val := range p.grid[row] {
+			if val == p.emptyTile.value || val == Blocked {
+				continue
+			}
+			nearest := -1
+			for goal := range p.colors {
+				if !p.accepts(goal, val) {
+					continue
+				}
+				if d := abs(row-goal/cols) + abs(col-goal%cols); nearest < 0 || d < nearest {
+					nearest = d
+				}
+			}
+			total += nearest
+		}
+	}
+	return total
+}
+
+// searchNode is a state reached by a best-first search.
+type searchNode struct {
+	puzzle Puzzle
+	key    string
+	g, h   int
+	parent *searchNode
+	move   Move
+}
+
+// path returns the moves leading from the search root to n.
+func (n *searchNode) path() []Move {
+	moves := []Move{}
+	for node := n; node.parent != nil; node = node.parent {
+		moves = append(moves, node.move)
+	}
+	slices.Reverse(moves)
+	return moves
+}
+
+// nodeQueue is a priority queue of search nodes ordered by g + weight*h,
+// breaking ties in favor of deeper nodes.
+type nodeQueue struct {
+	nodes  []*searchNode
+	weight float64
+}
+
+func (q *nodeQueue) priority(n *searchNode) float64 {
+	return float64(n.g) + q.weight*float64(n.h)
+}
+
+func (q *nodeQueue) Len() int { return len(q.nodes) }
+
+func (q *nodeQueue) Less(i, j int) bool {
+	fi, fj := q.priority(q.nodes[i]), q.priority(q.nodes[j])
+	if fi != fj {
+		return fi < fj
+	}
+	return q.nodes[i].g > q.nodes[j].g
+}
+
+func (q *nodeQueue) Swap(i, j int) { q.nodes[i], q.nodes[j] = q.nodes[j], q.nodes[i] }
+
+func (q *nodeQueue) Push(x any) { q.nodes = append(q.nodes, x.(*searchNode)) }
+
+func (q *nodeQueue) Pop() any {
+	n := q.nodes[len(q.nodes)-1]
+	q.nodes = q.nodes[:len(q.nodes)-1]
+	return n
+}
+
+// weightedAStar runs A* with the heuristic inflated by weight, minimizing the
+// total cost of the moves. With a consistent heuristic, the first solution
+// found costs at most weight times the optimal cost. States are re-opened when
+// a cheaper path to them is found, so that the open list always holds a state
+// on an optimal path with its optimal cost, which is what makes the lower
+// bound valid.
+func (p Puzzle) weightedAStar(weight float64, costs TileCosts) (CostSolution, error) {
+	if err := p.requireSingleEmpty(); err != nil {
+		return CostSolution{}, err
+	}
+	if !p.isSolvable() {
+		return CostSolution{}, UnsolvablePuzzleError{}
+	}
+	if p.isSolved() {
+		return CostSolution{Moves: []Move{}, Suboptimality: 1}, nil
+	}
+
+	start := &searchNode{puzzle: p, key: p.String(), h: costs.distance(p)}
+	open := &nodeQueue{weight: weight}
+	heap.Push(open, start)
+	bestG := map[string]int{start.key: 0}
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*searchNode)
+		if bestG[current.key] < current.g {
+			// A cheaper path to this state was found after it was queued.
+			continue
+		}
+
+		if current.puzzle.isSolved() {
+			return boundSolution(current, start.h, open, bestG), nil
+		}
+
+		for _, move := range allMoves {
+			if !current.puzzle.getMoves()[move] {
+				continue
+			}
+			next, err := current.puzzle.makeMove(move)
+			if err != nil {
+				return CostSolution{}, err
+			}
+
+			// The moved tile is now where the empty space was.
+			empty := current.puzzle.emptyTile.coord
+			g := current.g + costs.cost(next.grid[empty.row][empty.col])
+			key := next.String()
+			if best, seen := bestG[key]; seen && best <= g {
+				continue
+			}
+			bestG[key] = g
+			heap.Push(open, &searchNode{
+				puzzle: next,
+				key:    key,
+				g:      g,
+				h:      costs.distance(next),
+				parent: current,
+				move:   move,
+			})
+		}
+	}
+
+	return CostSolution{}, UnsolvablePuzzleError{}
+}
+
+// boundSolution proves a lower bound on the optimal solution cost from the
+// nodes left in the open list: one of them lies on an optimal path at its
+// optimal cost, so the smallest unweighted g + h among them cannot exceed the
+// optimal cost.
+func boundSolution(goal *searchNode, rootH int, open *nodeQueue, bestG map[string]int) CostSolution {
+	lower := goal.g
+	for _, n := range open.nodes {
+		if bestG[n.key] == n.g {
+			lower = min(lower, n.g+n.h)
+		}
+	}
+	lower = max(lower, rootH)
+	return CostSolution{
+		Moves:         goal.path(),
+		Cost:          goal.g,
+		LowerBound:    lower,
+		Suboptimality: float64(goal.g) / float64(lower),
+	}
+}
@@ -0,0 +1,139 @@
+package slide_puzzle
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestManhattanDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		grid [][]int
+		want int
+	}{
+		{
+			name: "solved puzzle",
+			grid: goalGrid(3, 3),
+			want: 0,
+		},
+		{
+			name: "empty tile is ignored",
+			grid: [][]int{
+				{1, 0, 2},
+				{3, 4, 5},
+				{6, 7, 8},
+			},
+			want: 1,
+		},
+		{
+			name: "corners swapped",
+			grid: [][]int{
+				{8, 1, 2},
+				{3, 4, 5},
+				{6, 7, 0},
+			},
+			want: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			puzzle, err := NewPuzzle(tt.grid, 0)
+			if err != nil {
+				t.Fatalf("NewPuzzle() error: %v", err)
+			}
+			if got := manhattanDistance(*puzzle); got != tt.want {
+				t.Errorf("manhattanDistance() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSolveBounded(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+
+	for _, epsilon := range []float64{0, 0.5, 2} {
+		for range 5 {
+			puzzle, err := Scramble(2, 4, 0, 60, rng)
+			if err != nil {
+				t.Fatalf("Scramble() error: %v", err)
+			}
+			optimal, err := puzzle.Solve()
+			if err != nil {
+				t.Fatalf("Solve() error: %v", err)
+			}
+
+			got, err := puzzle.SolveBounded(epsilon)
+			if err != nil {
+				t.Fatalf("SolveBounded(%v) error: %v", epsilon, err)
+			}
+			assertSolves(t, *puzzle, got.Moves)
+
+			if limit := (1 + epsilon) * float64(len(optimal)); float64(len(got.Moves)) > limit {
+				t.Errorf("SolveBounded(%v) found %d moves, want at most %v", epsilon, len(got.Moves), limit)
+			}
+			if got.LowerBound > len(optimal) {
+				t.Errorf("SolveBounded(%v) lower bound %d exceeds optimal length %d", epsilon, got.LowerBound, len(optimal))
+			}
+			if got.Suboptimality > 1+epsilon {
+				t.Errorf("SolveBounded(%v) proved suboptimality %v, want at most %v", epsilon, got.Suboptimality, 1+epsilon)
+			}
+			if epsilon == 0 && got.Suboptimality != 1 {
+				t.Errorf("SolveBounded(0) suboptimality = %v, want 1", got.Suboptimality)
+			}
+		}
+	}
+
+	t.Run("already solved puzzle", func(t *testing.T) {
+		puzzle, err := NewPuzzle(goalGrid(3, 3), 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		got, err := puzzle.SolveBounded(1)
+		if err != nil {
+			t.Fatalf("SolveBounded() error: %v", err)
+		}
+		if len(got.Moves) != 0 || got.Suboptimality != 1 {
+			t.Errorf("SolveBounded() = %+v, want no moves with suboptimality 1", got)
+		}
+	})
+
+	t.Run("larger board", func(t *testing.T) {
+		puzzle, err := Scramble(4, 4, 0, 200, rng)
+		if err != nil {
+			t.Fatalf("Scramble() error: %v", err)
+		}
+		got, err := puzzle.SolveBounded(1)
+		if err != nil {
+			t.Fatalf("SolveBounded() error: %v", err)
+		}
+		assertSolves(t, *puzzle, got.Moves)
+	})
+
+	t.Run("unsolvable puzzle returns error", func(t *testing.T) {
+		grid := goalGrid(3, 3)
+		grid[2][1], grid[2][2] = grid[2][2], grid[2][1]
+		puzzle, err := NewPuzzle(grid, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		_, err = puzzle.SolveBounded(1)
+		if !errors.As(err, &UnsolvablePuzzleError{}) {
+			t.Fatalf("SolveBounded() error = %v, want UnsolvablePuzzleError", err)
+		}
+	})
+
+	t.Run("invalid epsilon returns error", func(t *testing.T) {
+		puzzle, err := NewPuzzle(goalGrid(2, 2), 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		for _, epsilon := range []float64{-0.1, math.NaN(), math.Inf(1)} {
+			if _, err := puzzle.SolveBounded(epsilon); err == nil {
+				t.Errorf("SolveBounded(%v) error = nil, want error", epsilon)
+			}
+		}
+	})
+}
@@ -0,0 +1,381 @@
+package slide_puzzle
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Blocked marks a grid cell that is permanently blocked: it never holds a tile
+// and tiles cannot move through it. Blocked cells let a rectangular grid model
+// boards with walls or irregular outlines.
+//
+// Tile values still name goal positions in row-major order over the whole
+// grid, so the values that would belong in blocked cells are not used.
+const Blocked = math.MinInt
+
+// hasBlocked reports whether any of p's cells are blocked.
+func (p Puzzle) hasBlocked() bool {
+	for _, row := range p.grid {
+		for _, val := range row {
+			if val == Blocked {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isOpen reports whether c is on the board and not blocked.
+func (p Puzzle) isOpen(c coord) bool {
+	return c.row >= 0 && c.row < len(p.grid) && c.col >= 0 && c.col < len(p.grid[0]) &&
+		p.grid[c.row][c.col] != Blocked
+}
+
+// requireNoBlocked returns an error if p has blocked cells, for solvers that
+// rely on a full rectangular board.
+func (p Puzzle) requireNoBlocked() error {
+	if p.hasBlocked() {
+		return &InvalidPuzzleError{"this solver does not support blocked cells"}
+	}
+	return nil
+}
+
+// Solvability is the result of analyzing whether a puzzle can be solved.
+type Solvability int
+
+const (
+	Solvable Solvability = iota
+	Unsolvable
+	// SolvabilityUnknown means the analysis could not decide. Searching for a
+	// solution will tell, but may take a long time.
+	SolvabilityUnknown
+)
+
+var solvabilityStrings = map[Solvability]string{
+	Solvable:           "solvable",
+	Unsolvable:         "unsolvable",
+	SolvabilityUnknown: "unknown",
+}
+
+func (s Solvability) String() string {
+	return solvabilityStrings[s]
+}
+
+// Solvability reports whether p can be solved, without searching.
+//
+// It is always decided for rectangular boards. With blocked cells, the open
+// cells form a graph, and a tile can only ever reach cells in its own
+// connected part of it; parts without the empty tile cannot change at all. In
+// the empty tile's part, the outcome is decided when it is a path, on which
+// tiles keep their order; a cycle, on which they keep their cyclic order; or
+// has no cell whose removal would disconnect it, in which case the parity rule
+// for rectangular boards still applies. Other shapes, such as rooms joined by
+// one-cell corridors, are reported as SolvabilityUnknown.
+//
+// Colored tiles only need to reach some cell of their color, so on shapes
+// decided by the parity rule, two tiles of the same color make any
+// arrangement solvable. Partial goals with wildcard cells on boards with
+// blocked cells are reported as SolvabilityUnknown.
+func (p Puzzle) Solvability() Solvability {
+	if !p.hasBlocked() {
+		if p.isSolvable() {
+			return Solvable
+		}
+		return Unsolvable
+	}
+	return newCellGraph(p).solvability(p)
+}
+
+// cellGraph is the graph of a puzzle's open cells, indexed in row-major order,
+// with edges between neighbors.
+type cellGraph struct {
+	neighbors [][]int
+	// component numbers the connected part of the graph each cell is in, or
+	// -1 for blocked cells.
+	component []int
+}
+
+func newCellGraph(p Puzzle) *cellGraph {
+	rows, cols := len(p.grid), len(p.grid[0])
+	g := &cellGraph{neighbors: make([][]int, rows*cols), component: make([]int, rows*cols)}
+	for i := range g.component {
+		g.component[i] = -1
+	}
+	for row := range p.grid {
+		for col := range p.grid[row] {
+			here := coord{row: row, col: col}
+			if !p.isOpen(here) {
+				continue
+			}
+			for _, m := range allMoves {
+				if c := m.source(here); p.isOpen(c) {
+					g.neighbors[here.index(cols)] = append(g.neighbors[here.index(cols)], c.index(cols))
+				}
+			}
+		}
+	}
+
+	next := 0
+	for start := range g.component {
+		if g.component[start] >= 0 || !p.isOpen(coord{row: start / cols, col: start % cols}) {
+			continue
+		}
+		queue := []int{start}
+		g.component[start] = next
+		for len(queue) > 0 {
+			cell := queue[0]
+			queue = queue[1:]
+			for _, n := range g.neighbors[cell] {
+				if g.component[n] < 0 {
+					g.component[n] = next
+					queue = append(queue, n)
+				}
+			}
+		}
+		next++
+	}
+	return g
+}
+
+func (g *cellGraph) solvability(p Puzzle) Solvability {
+	cols := len(p.grid[0])
+	if p.wildcards != nil {
+		// Which tiles end up in wildcard cells is not fixed, so none of the
+		// rules below apply directly.
+		return SolvabilityUnknown
+	}
+	values := p.Values()
+	if p.colors != nil {
+		if values = g.relabel(p); values == nil {
+			return Unsolvable
+		}
+	}
+	empty := p.emptyTile.coord.index(cols)
+	part := g.component[empty]
+
+	// Every tile must be able to reach its goal, and tiles away from the empty
+	// tile cannot move.
+	var cells []int
+	for i, val := range values {
+		if val == Blocked {
+			continue
+		}
+		if g.component[i] != g.component[val] {
+			return Unsolvable
+		}
+		if g.component[i] == part {
+			cells = append(cells, i)
+		} else if val != i {
+			return Unsolvable
+		}
+	}
+
+	maxDegree := 0
+	for _, cell := range cells {
+		maxDegree = max(maxDegree, len(g.neighbors[cell]))
+	}
+	switch {
+	case maxDegree <= 2:
+		// A path or a cycle: walk it from one end, or from anywhere on a
+		// cycle, and compare the order of the tiles with their goal order.
+		start := cells[0]
+		for _, cell := range cells {
+			if len(g.neighbors[cell]) < 2 {
+				start = cell
+				break
+			}
+		}
+		isCycle := len(g.neighbors[start]) == 2
+		var current, goal []int
+		prev := -1
+		for cell := start; ; {
+			if values[cell] != p.emptyTile.value {
+				current = append(current, p.color(values[cell]))
+			}
+			if cell != p.emptyTile.value {
+				goal = append(goal, p.color(cell))
+			}
+			next := -1
+			for _, n := range g.neighbors[cell] {
+				if n != prev {
+					next = n
+					break
+				}
+			}
+			if next < 0 || next == start {
+				break
+			}
+			prev, cell = cell, next
+		}
+		if sameOrder(current, goal, isCycle) {
+			return Solvable
+		}
+		return Unsolvable
+
+	case g.hasCutCell(cells):
+		return SolvabilityUnknown
+
+	case hasTwinsOn(p, cells, values):
+		// As on rectangular boards, swapping two tiles of the same color
+		// fixes the parity.
+		return Solvable
+
+	default:
+		// Grid graphs are bipartite, so by Wilson's theorem on sliding
+		// puzzles on graphs, exactly the even permutations (adjusted for the
+		// empty tile's distance) are reachable, as on a rectangular board.
+		if permutationParity(values) == p.emptyDistance()%2 {
+			return Solvable
+		}
+		return Unsolvable
+	}
+}
+
+// relabel reassigns the values of p's colored tiles so that each tile gets the
+// goal position of a cell of its color in its own connected part, keeping
+// tiles of the same color in row-major order. It returns nil if some part does
+// not hold the tiles its goal needs.
+func (g *cellGraph) relabel(p Puzzle) []int {
+	type class struct{ part, color int }
+	values := p.Values()
+	goals := map[class][]int{}
+	for cell, val := range values {
+		if val != Blocked {
+			c := class{part: g.component[cell], color: p.colors[cell]}
+			goals[c] = append(goals[c], cell)
+		}
+	}
+	for cell, val := range values {
+		if val == Blocked {
+			continue
+		}
+		c := class{part: g.component[cell], color: p.colors[val]}
+		if len(goals[c]) == 0 {
+			return nil
+		}
+		values[cell], goals[c] = goals[c][0], goals[c][1:]
+	}
+	return values
+}
+
+// hasTwinsOn reports whether two tiles other than the empty one on cells have
+// the same color.
+func hasTwinsOn(p Puzzle, cells, values []int) bool {
+	seen := map[int]bool{}
+	for _, cell := range cells {
+		if values[cell] == p.emptyTile.value {
+			continue
+		}
+		color := p.color(values[cell])
+		if seen[color] {
+			return true
+		}
+		seen[color] = true
+	}
+	return false
+}
+
+// sameOrder reports whether current lists the same values as goal in the same
+// order, or for a cycle, the same order after some rotation.
+func sameOrder(current, goal []int, cycle bool) bool {
+	if len(current) != len(goal) {
+		return false
+	}
+	shifts := 1
+	if cycle {
+		shifts = len(goal)
+	}
+	for shift := range shifts {
+		match := true
+		for i := range goal {
+			if current[(i+shift)%len(current)] != goal[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return len(goal) == 0
+}
+
+// hasCutCell reports whether removing any one of cells, which must form a
+// connected part of the graph, would disconnect the rest.
+func (g *cellGraph) hasCutCell(cells []int) bool {
+	// Tarjan's algorithm, using the lowest discovery time reachable from
+	// each cell's subtree through at most one back edge.
+	discovered := map[int]int{}
+	low := map[int]int{}
+	found := false
+	var visit func(cell, parent int)
+	visit = func(cell, parent int) {
+		discovered[cell] = len(discovered)
+		low[cell] = discovered[cell]
+		children := 0
+		for _, n := range g.neighbors[cell] {
+			if n == parent {
+				continue
+			}
+			if _, seen := discovered[n]; seen {
+				low[cell] = min(low[cell], discovered[n])
+				continue
+			}
+			children++
+			visit(n, cell)
+			low[cell] = min(low[cell], low[n])
+			if parent >= 0 && low[n] >= discovered[cell] {
+				found = true
+			}
+		}
+		if parent < 0 && children > 1 {
+			found = true
+		}
+	}
+	visit(cells[0], -1)
+	return found
+}
+
+// ReadPuzzle reads a puzzle in the text form described by ReadGrid.
+func ReadPuzzle(r io.Reader, emptyTileValue int) (*Puzzle, error) {
+	grid, err := ReadGrid(r)
+	if err != nil {
+		return nil, err
+	}
+	return NewPuzzle(grid, emptyTileValue)
+}
+
+// ReadGrid reads a puzzle grid in text form: one line per row, with the values
+// in each row separated by spaces. A "#" marks a blocked cell. Blank lines are
+// ignored.
+func ReadGrid(r io.Reader) ([][]int, error) {
+	var grid [][]int
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		row := make([]int, len(fields))
+		for i, field := range fields {
+			if field == "#" {
+				row[i] = Blocked
+				continue
+			}
+			val, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, &InvalidPuzzleError{fmt.Sprintf("invalid value %q in row %d", field, len(grid))}
+			}
+			row[i] = val
+		}
+		grid = append(grid, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return grid, nil
+}
@@ -0,0 +1,175 @@
+package slide_puzzle
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// randomBlockedPuzzle returns a rows x cols puzzle, where the cells at the
+// given row-major indexes are blocked, with the empty tile in the first open
+// cell. If shuffle is true, the open cells hold a random arrangement of their
+// goal values; otherwise the puzzle is scrambled by random moves from the goal,
+// so it is always solvable.
+func randomBlockedPuzzle(t *testing.T, rng *rand.Rand, rows, cols int, shuffle bool, blocked ...int) *Puzzle {
+	t.Helper()
+	grid := make([][]int, rows)
+	var open []int
+	for row := range grid {
+		grid[row] = make([]int, cols)
+		for col := range grid[row] {
+			grid[row][col] = row*cols + col
+			if slices.Contains(blocked, row*cols+col) {
+				grid[row][col] = Blocked
+			} else {
+				open = append(open, row*cols+col)
+			}
+		}
+	}
+	if shuffle {
+		values := slices.Clone(open)
+		rng.Shuffle(len(values), func(i, j int) { values[i], values[j] = values[j], values[i] })
+		for i, cell := range open {
+			grid[cell/cols][cell%cols] = values[i]
+		}
+	}
+	p, err := NewPuzzle(grid, open[0])
+	if err != nil {
+		t.Fatalf("NewPuzzle() error: %v", err)
+	}
+	if shuffle {
+		return p
+	}
+
+	for range 20 {
+		var moves []Move
+		for move, ok := range p.getMoves() {
+			if ok {
+				moves = append(moves, move)
+			}
+		}
+		slices.Sort(moves)
+		next, err := p.makeMove(moves[rng.IntN(len(moves))])
+		if err != nil {
+			t.Fatalf("makeMove() error: %v", err)
+		}
+		p = &next
+	}
+	return p
+}
+
+func TestNewPuzzleBlocked(t *testing.T) {
+	t.Run("blocked cells are allowed", func(t *testing.T) {
+		p, err := NewPuzzle([][]int{{0, Blocked}, {2, 3}}, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		if !p.isSolved() {
+			t.Errorf("isSolved() = false for %v", p)
+		}
+		if moves := p.getMoves(); moves[West] || !moves[North] {
+			t.Errorf("getMoves() = %v, want North only", moves)
+		}
+	})
+
+	t.Run("value belonging in a blocked cell returns error", func(t *testing.T) {
+		_, err := NewPuzzle([][]int{{0, Blocked}, {1, 3}}, 0)
+		var invalidErr *InvalidPuzzleError
+		if !errors.As(err, &invalidErr) {
+			t.Errorf("NewPuzzle() error type = %T, want *InvalidPuzzleError", err)
+		}
+	})
+}
+
+func TestReadPuzzle(t *testing.T) {
+	t.Run("blocked cells", func(t *testing.T) {
+		p, err := ReadPuzzle(strings.NewReader("4 1 2\n\n# 0 5\n"), 0)
+		if err != nil {
+			t.Fatalf("ReadPuzzle() error: %v", err)
+		}
+		want, err := NewPuzzle([][]int{{4, 1, 2}, {Blocked, 0, 5}}, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		assertPuzzlesEqual(t, want, p)
+	})
+
+	t.Run("invalid value returns error", func(t *testing.T) {
+		_, err := ReadPuzzle(strings.NewReader("0 1\n2 x\n"), 0)
+		var invalidErr *InvalidPuzzleError
+		if !errors.As(err, &invalidErr) {
+			t.Errorf("ReadPuzzle() error type = %T, want *InvalidPuzzleError", err)
+		}
+	})
+}
+
+func TestSolvabilityBlocked(t *testing.T) {
+	layouts := []struct {
+		name       string
+		rows, cols int
+		blocked    []int
+		want       []Solvability
+	}{
+		{name: "2x3 path", rows: 2, cols: 3, blocked: []int{1}, want: []Solvability{Solvable, Unsolvable}},
+		{name: "3x3 ring", rows: 3, cols: 3, blocked: []int{4}, want: []Solvability{Solvable, Unsolvable}},
+		{name: "3x3 missing corner", rows: 3, cols: 3, blocked: []int{8}, want: []Solvability{Solvable, Unsolvable}},
+		{name: "3x3 dead end", rows: 3, cols: 3, blocked: []int{1}, want: []Solvability{SolvabilityUnknown}},
+		{name: "2x3 split", rows: 2, cols: 3, blocked: []int{1, 4}, want: []Solvability{Unsolvable}},
+	}
+
+	rng := rand.New(rand.NewPCG(4, 2))
+	for _, layout := range layouts {
+		t.Run(layout.name, func(t *testing.T) {
+			seen := map[Solvability]bool{}
+			for i := range 12 {
+				p := randomBlockedPuzzle(t, rng, layout.rows, layout.cols, i%2 == 0, layout.blocked...)
+				got := p.Solvability()
+				seen[got] = true
+				if got == SolvabilityUnknown {
+					continue
+				}
+				_, err := p.Solve()
+				if solvable := err == nil; solvable != (got == Solvable) {
+					t.Errorf("Solvability() = %v for %v, but Solve() error = %v", got, p, err)
+				}
+			}
+			for _, want := range layout.want {
+				if !seen[want] {
+					t.Errorf("Solvability() never returned %v for %d random puzzles", want, 12)
+				}
+			}
+		})
+	}
+}
+
+func TestSolveBlocked(t *testing.T) {
+	p, err := ReadPuzzle(strings.NewReader("4 0 1 3\n8 # 2 7\n9 10 6 11\n"), 0)
+	if err != nil {
+		t.Fatalf("ReadPuzzle() error: %v", err)
+	}
+	want, err := p.Solve()
+	if err != nil {
+		t.Fatalf("Solve() error: %v", err)
+	}
+
+	ida, err := p.SolveIDAStar(2)
+	if err != nil {
+		t.Fatalf("SolveIDAStar() error: %v", err)
+	}
+	hda, err := p.SolveHDAStar(2)
+	if err != nil {
+		t.Fatalf("SolveHDAStar() error: %v", err)
+	}
+	for name, got := range map[string][]Move{"Solve": want, "SolveIDAStar": ida, "SolveHDAStar": hda} {
+		assertSolves(t, *p, got)
+		if len(got) != len(want) {
+			t.Errorf("%s() took %d moves, want %d", name, len(got), len(want))
+		}
+	}
+
+	if _, err := p.SolveConstructive(); err == nil {
+		t.Errorf("SolveConstructive() error = nil, want error for blocked cells")
+	}
+}
@@ -0,0 +1,299 @@
+package slide_puzzle
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Wildcard marks a goal cell that accepts any tile, in goals passed to
+// NewColoredPuzzle.
+const Wildcard = math.MinInt + 1
+
+// NewColoredPuzzle returns a puzzle whose tiles are told apart only by color,
+// as in color-sorting variants: tiles of the same color are interchangeable,
+// and the puzzle is solved when every cell holds a tile of the color goal
+// gives for it. grid holds the color of each tile and goal the color wanted
+// in each cell; colors are any integers other than Blocked and Wildcard, and
+// may repeat. Exactly one cell of grid holds emptyColor, the color of the
+// empty tile. Blocked cells must be the same in both.
+//
+// Goal cells may also be Wildcard, for partial goals where those cells can
+// hold any tile, including the empty one. Each color then needs at least as
+// many tiles in grid as cells in goal, and the remaining tiles fill the
+// wildcard cells. If goal does not contain emptyColor, the empty tile can end
+// in any wildcard cell.
+//
+// Internally, each tile is assigned the goal position of a cell of its color,
+// or of a wildcard cell, so the puzzle can be used wherever a puzzle with
+// distinct tiles can. States that differ only in which of two same-colored
+// tiles is where are treated as the same state. Solve, SolveBounded and
+// SolveAnytime support colored tiles.
+func NewColoredPuzzle(grid, goal [][]int, emptyColor int) (*Puzzle, error) {
+	if len(grid) == 0 || len(goal) != len(grid) {
+		return nil, &InvalidPuzzleError{fmt.Sprintf("grid and goal must have the same number of rows; got %d and %d", len(grid), len(goal))}
+	}
+	cols := len(goal[0])
+
+	// The goal positions of each color and the wildcard cells, in row-major
+	// order.
+	goalCells := map[int][]int{}
+	var wildCells []int
+	wildcards := make([]bool, len(goal)*cols)
+	colors := make([]int, len(goal)*cols)
+	emptyCount := 0
+	for row := range goal {
+		if len(goal[row]) != cols || len(grid[row]) != cols {
+			return nil, &InvalidPuzzleError{fmt.Sprintf("all rows of grid and goal must have %d columns", cols)}
+		}
+		for col, color := range goal[row] {
+			cell := row*cols + col
+			switch {
+			case (color == Blocked) != (grid[row][col] == Blocked):
+				return nil, &InvalidPuzzleError{fmt.Sprintf("grid and goal must have the same blocked cells; they differ at (%d, %d)", row, col)}
+			case grid[row][col] == Wildcard:
+				return nil, &InvalidPuzzleError{fmt.Sprintf("grid cannot contain wildcards; found one at (%d, %d)", row, col)}
+			case color == Wildcard:
+				wildcards[cell] = true
+				wildCells = append(wildCells, cell)
+			case color != Blocked:
+				goalCells[color] = append(goalCells[color], cell)
+			}
+			colors[cell] = color
+			if grid[row][col] == emptyColor {
+				emptyCount++
+			}
+		}
+	}
+	if emptyCount != 1 {
+		return nil, &InvalidPuzzleError{fmt.Sprintf("puzzle must contain exactly one empty tile; got %d", emptyCount)}
+	}
+	if n := len(goalCells[emptyColor]); n > 1 {
+		return nil, &InvalidPuzzleError{fmt.Sprintf("goal must contain at most one empty tile; got %d", n)}
+	}
+
+	values := make([][]int, len(grid))
+	emptyValue := -1
+	for row := range grid {
+		values[row] = make([]int, cols)
+		for col, color := range grid[row] {
+			if color == Blocked {
+				values[row][col] = Blocked
+				continue
+			}
+			var cell int
+			switch {
+			case len(goalCells[color]) > 0:
+				cell, goalCells[color] = goalCells[color][0], goalCells[color][1:]
+			case len(wildCells) > 0:
+				// Tiles a wildcard cell takes keep their own color.
+				cell, wildCells = wildCells[0], wildCells[1:]
+				colors[cell] = color
+			default:
+				return nil, &InvalidPuzzleError{fmt.Sprintf("grid has more tiles of color %d than goal", color)}
+			}
+			values[row][col] = cell
+			if color == emptyColor {
+				emptyValue = cell
+			}
+		}
+	}
+	for color, cells := range goalCells {
+		if len(cells) > 0 {
+			return nil, &InvalidPuzzleError{fmt.Sprintf("goal has more tiles of color %d than grid", color)}
+		}
+	}
+
+	p, err := NewPuzzle(values, emptyValue)
+	if err != nil {
+		return nil, err
+	}
+	p.colors = colors
+	if slices.Contains(wildcards, true) {
+		p.wildcards = wildcards
+	}
+	return p, nil
+}
+
+// WithGoal returns p with a different goal, given in the terms of p.Colors():
+// goal holds the color, or for puzzles without colors the tile value, wanted
+// in each cell, or Wildcard for cells that can hold any tile. See
+// NewColoredPuzzle.
+func (p Puzzle) WithGoal(goal [][]int) (*Puzzle, error) {
+	if err := p.requireSingleEmpty(); err != nil {
+		return nil, err
+	}
+	return NewColoredPuzzle(p.Colors(), goal, p.color(p.emptyTile.value))
+}
+
+// PartialGoal returns p with a goal that only asks for the given tiles to be
+// at their goal positions, leaving every other cell a wildcard. For example,
+// PartialGoal(1, 2, 3) on a 4x4 puzzle only asks for the first row to be
+// solved. Solving partial goals in stages, and the full puzzle last, can be
+// much faster than solving it at once.
+func (p Puzzle) PartialGoal(tiles ...int) (*Puzzle, error) {
+	if err := p.requireDistinct(); err != nil {
+		return nil, err
+	}
+	goal := make([][]int, len(p.grid))
+	for row := range goal {
+		goal[row] = make([]int, len(p.grid[row]))
+		for col, val := range p.grid[row] {
+			goal[row][col] = Wildcard
+			if val == Blocked {
+				goal[row][col] = Blocked
+			}
+		}
+	}
+	cols := len(p.grid[0])
+	for _, val := range tiles {
+		if val < 0 || val >= len(p.grid)*cols || goal[val/cols][val%cols] == Blocked {
+			return nil, &InvalidPuzzleError{fmt.Sprintf("tile %d is not on the board", val)}
+		}
+		goal[val/cols][val%cols] = val
+	}
+	return p.WithGoal(goal)
+}
+
+// goalColor returns the color wanted in cell, or Wildcard if it accepts any
+// tile. Without colors, it is the value of the tile that belongs there.
+func (p Puzzle) goalColor(cell int) int {
+	if p.wildcards != nil && p.wildcards[cell] {
+		return Wildcard
+	}
+	return p.color(cell)
+}
+
+// accepts reports whether the tile with value val can be in cell in the goal
+// state.
+func (p Puzzle) accepts(cell, val int) bool {
+	g := p.goalColor(cell)
+	return g == Wildcard || g == p.color(val)
+}
+
+// color returns the color of the tile with value val. Without colors, every
+// tile is its own color.
+func (p Puzzle) color(val int) int {
+	if p.colors == nil {
+		return val
+	}
+	return p.colors[val]
+}
+
+// Colors returns the color of each tile, laid out like the grid. For puzzles
+// without colors, these are the tile values.
+func (p Puzzle) Colors() [][]int {
+	grid := make([][]int, len(p.grid))
+	for row := range p.grid {
+		grid[row] = make([]int, len(p.grid[row]))
+		for col, val := range p.grid[row] {
+			if val != Blocked {
+				val = p.color(val)
+			}
+			grid[row][col] = val
+		}
+	}
+	return grid
+}
+
+// colorValues returns the colors of the tiles in row-major order.
+func (p Puzzle) colorValues() []int {
+	return slices.Concat(p.Colors()...)
+}
+
+// hasTwins reports whether any two tiles other than the empty one are
+// interchangeable in the goal: they have the same color, or both fill
+// wildcard cells. Swapping such a pair changes the parity of the permutation
+// without changing whether the state is solved, so either parity can be
+// solved.
+func (p Puzzle) hasTwins() bool {
+	seen := map[int]bool{}
+	wild := 0
+	for _, val := range p.Values() {
+		if val == Blocked || val == p.emptyTile.value {
+			continue
+		}
+		// Tiles of the same color can swap wherever they are, even if one
+		// was assigned a wildcard cell and the other a cell of its color.
+		if seen[p.color(val)] {
+			return true
+		}
+		seen[p.color(val)] = true
+		// Each tile's value is the goal position assigned to it.
+		if p.goalColor(val) == Wildcard {
+			if wild++; wild == 2 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasLooseEmpty reports whether the empty tile can end in either of two
+// wildcard cells an even distance apart. Moving it from one to the other
+// takes an even number of moves but swaps it with a tile, so this too makes
+// either parity solvable.
+func (p Puzzle) hasLooseEmpty() bool {
+	// Without a cell of its own color, the empty tile was assigned a
+	// wildcard cell and can end in any of them.
+	if p.goalColor(p.emptyTile.value) != Wildcard {
+		return false
+	}
+	cols := len(p.grid[0])
+	var count [2]int
+	for cell, wild := range p.wildcards {
+		if !wild {
+			continue
+		}
+		if count[(cell/cols+cell%cols)%2]++; count[(cell/cols+cell%cols)%2] == 2 {
+			return true
+		}
+	}
+	return false
+}
+
+// lineSolvable reports whether a puzzle with colored tiles on a single row or
+// column can be solved. Tiles cannot pass each other, so their colors must
+// already be in the goal's order, with wildcard cells matching any color, for
+// some cell the empty tile can end in.
+func (p Puzzle) lineSolvable() bool {
+	values := p.Values()
+	emptyColor := p.color(p.emptyTile.value)
+	var tiles []int
+	for _, val := range values {
+		if val != p.emptyTile.value {
+			tiles = append(tiles, p.color(val))
+		}
+	}
+
+	for end := range values {
+		if g := p.goalColor(end); g != Wildcard && g != emptyColor {
+			continue
+		}
+		i := 0
+		match := true
+		for cell := range values {
+			if cell == end {
+				continue
+			}
+			if g := p.goalColor(cell); g != Wildcard && g != tiles[i] {
+				match = false
+				break
+			}
+			i++
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// requireDistinct returns an error if p has colored tiles, for solvers that
+// rely on every tile having its own goal position.
+func (p Puzzle) requireDistinct() error {
+	if p.colors != nil {
+		return &InvalidPuzzleError{"this solver does not support colored tiles"}
+	}
+	return nil
+}
@@ -0,0 +1,324 @@
+package slide_puzzle
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewColoredPuzzle(t *testing.T) {
+	goal := [][]int{{0, 1, 1}, {2, 2, 1}}
+
+	t.Run("valid puzzle", func(t *testing.T) {
+		grid := [][]int{{1, 0, 1}, {2, 1, 2}}
+		p, err := NewColoredPuzzle(grid, goal, 0)
+		if err != nil {
+			t.Fatalf("NewColoredPuzzle() error: %v", err)
+		}
+		if diff := cmp.Diff(grid, p.Colors()); diff != "" {
+			t.Errorf("Colors() mismatch (-want +got):\n%s", diff)
+		}
+		if p.isSolved() {
+			t.Errorf("isSolved() = true for %v", p)
+		}
+	})
+
+	t.Run("solved in any arrangement of same-colored tiles", func(t *testing.T) {
+		p, err := NewColoredPuzzle(goal, goal, 0)
+		if err != nil {
+			t.Fatalf("NewColoredPuzzle() error: %v", err)
+		}
+		// Swap which of the two tiles of color 1 in the top row is which.
+		p.grid[0][1], p.grid[0][2] = p.grid[0][2], p.grid[0][1]
+		if !p.isSolved() {
+			t.Errorf("isSolved() = false for %v", p)
+		}
+	})
+
+	for name, grid := range map[string][][]int{
+		"missing color":   {{0, 1, 1}, {2, 2, 2}},
+		"two empty tiles": {{0, 1, 1}, {2, 0, 1}},
+		"blocked differs": {{0, 1, 1}, {2, 2, Blocked}},
+		"wrong shape":     {{0, 1, 1, 2}, {2, 1}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewColoredPuzzle(grid, goal, 0)
+			var invalidErr *InvalidPuzzleError
+			if !errors.As(err, &invalidErr) {
+				t.Errorf("NewColoredPuzzle(%v) error type = %T, want *InvalidPuzzleError", grid, err)
+			}
+		})
+	}
+}
+
+func TestSolveColored(t *testing.T) {
+	t.Run("one move", func(t *testing.T) {
+		goal := [][]int{{0, 1, 1}, {2, 2, 2}}
+		p, err := NewColoredPuzzle([][]int{{1, 0, 1}, {2, 2, 2}}, goal, 0)
+		if err != nil {
+			t.Fatalf("NewColoredPuzzle() error: %v", err)
+		}
+		got, err := p.Solve()
+		if err != nil {
+			t.Fatalf("Solve() error: %v", err)
+		}
+		if diff := cmp.Diff([]Move{East}, got); diff != "" {
+			t.Errorf("Solve() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("twins fix the parity", func(t *testing.T) {
+		// With distinct tiles, swapping 3 with a tile of color 2 would be
+		// unsolvable.
+		goal := [][]int{{0, 1, 1}, {2, 2, 3}}
+		p, err := NewColoredPuzzle([][]int{{0, 1, 1}, {2, 3, 2}}, goal, 0)
+		if err != nil {
+			t.Fatalf("NewColoredPuzzle() error: %v", err)
+		}
+		if !p.isSolvable() {
+			t.Fatalf("isSolvable() = false for %v", p)
+		}
+		want, err := p.Solve()
+		if err != nil {
+			t.Fatalf("Solve() error: %v", err)
+		}
+		assertSolves(t, *p, want)
+
+		bounded, err := p.SolveBounded(0)
+		if err != nil {
+			t.Fatalf("SolveBounded() error: %v", err)
+		}
+		assertSolves(t, *p, bounded.Moves)
+		if len(bounded.Moves) != len(want) {
+			t.Errorf("SolveBounded(0) took %d moves, want %d", len(bounded.Moves), len(want))
+		}
+	})
+
+	t.Run("tiles on a row keep their order", func(t *testing.T) {
+		p, err := NewColoredPuzzle([][]int{{0, 2, 1, 1}}, [][]int{{0, 1, 1, 2}}, 0)
+		if err != nil {
+			t.Fatalf("NewColoredPuzzle() error: %v", err)
+		}
+		if p.isSolvable() {
+			t.Errorf("isSolvable() = true for %v", p)
+		}
+	})
+
+	t.Run("solvers needing distinct tiles return error", func(t *testing.T) {
+		p, err := NewColoredPuzzle([][]int{{1, 0, 1}, {2, 2, 2}}, [][]int{{0, 1, 1}, {2, 2, 2}}, 0)
+		if err != nil {
+			t.Fatalf("NewColoredPuzzle() error: %v", err)
+		}
+		_, err = p.SolveIDAStar(1)
+		var invalidErr *InvalidPuzzleError
+		if !errors.As(err, &invalidErr) {
+			t.Errorf("SolveIDAStar() error type = %T, want *InvalidPuzzleError", err)
+		}
+	})
+}
+
+func TestSolvabilityColoredBlocked(t *testing.T) {
+	// The open cells form the path 0, 3, 4, 5, 2, on which the goal colors
+	// read 2 1 2 1 after the empty tile.
+	goal := [][]int{{0, Blocked, 1}, {2, 1, 2}}
+	tests := []struct {
+		name string
+		grid [][]int
+		want Solvability
+	}{
+		{name: "same order", grid: [][]int{{2, Blocked, 1}, {0, 1, 2}}, want: Solvable},
+		{name: "different order", grid: [][]int{{0, Blocked, 2}, {1, 2, 1}}, want: Unsolvable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewColoredPuzzle(tt.grid, goal, 0)
+			if err != nil {
+				t.Fatalf("NewColoredPuzzle() error: %v", err)
+			}
+			if got := p.Solvability(); got != tt.want {
+				t.Errorf("Solvability() = %v, want %v", got, tt.want)
+			}
+			if _, err := p.Solve(); (err == nil) != (tt.want == Solvable) {
+				t.Errorf("Solve() error = %v, want solvable %v", err, tt.want == Solvable)
+			}
+		})
+	}
+}
+
+func TestCanonicalColored(t *testing.T) {
+	goal := [][]int{{0, 1, 2}, {1, 3, 3}, {2, 3, 3}}
+	p, err := NewColoredPuzzle([][]int{{3, 1, 2}, {0, 3, 3}, {2, 3, 1}}, goal, 0)
+	if err != nil {
+		t.Fatalf("NewColoredPuzzle() error: %v", err)
+	}
+	mirrored := p.Transform(Transpose)
+	if diff := cmp.Diff([][]int{{3, 0, 2}, {1, 3, 3}, {2, 3, 1}}, mirrored.Colors()); diff != "" {
+		t.Errorf("Transform(Transpose).Colors() mismatch (-want +got):\n%s", diff)
+	}
+	if got, want := mirrored.Canonical().String(), p.Canonical().String(); got != want {
+		t.Errorf("Canonical() of mirror image = %s, want %s", got, want)
+	}
+}
+
+func TestPartialGoal(t *testing.T) {
+	p, err := Scramble(3, 3, 0, 30, rand.New(rand.NewPCG(4, 6)))
+	if err != nil {
+		t.Fatalf("Scramble() error: %v", err)
+	}
+	full, err := p.Solve()
+	if err != nil {
+		t.Fatalf("Solve() error: %v", err)
+	}
+
+	t.Run("no tiles", func(t *testing.T) {
+		partial, err := p.PartialGoal()
+		if err != nil {
+			t.Fatalf("PartialGoal() error: %v", err)
+		}
+		got, err := partial.Solve()
+		if err != nil {
+			t.Fatalf("Solve() error: %v", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("Solve() = %v, want no moves", got)
+		}
+	})
+
+	t.Run("first row", func(t *testing.T) {
+		partial, err := p.PartialGoal(0, 1, 2)
+		if err != nil {
+			t.Fatalf("PartialGoal() error: %v", err)
+		}
+		got, err := partial.Solve()
+		if err != nil {
+			t.Fatalf("Solve() error: %v", err)
+		}
+		assertSolves(t, *partial, got)
+		if len(got) > len(full) {
+			t.Errorf("Solve() took %d moves, more than the %d for the full goal", len(got), len(full))
+		}
+
+		bounded, err := partial.SolveBounded(0)
+		if err != nil {
+			t.Fatalf("SolveBounded() error: %v", err)
+		}
+		if len(bounded.Moves) != len(got) {
+			t.Errorf("SolveBounded(0) took %d moves, want %d", len(bounded.Moves), len(got))
+		}
+
+		// Continue from where the first stage left off.
+		current := *p
+		for _, m := range got {
+			if current, err = current.makeMove(m); err != nil {
+				t.Fatalf("makeMove() error: %v", err)
+			}
+		}
+		if diff := cmp.Diff([]int{0, 1, 2}, current.Values()[:3]); diff != "" {
+			t.Errorf("first row after Solve() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("tile not on the board returns error", func(t *testing.T) {
+		_, err := p.PartialGoal(9)
+		var invalidErr *InvalidPuzzleError
+		if !errors.As(err, &invalidErr) {
+			t.Errorf("PartialGoal() error type = %T, want *InvalidPuzzleError", err)
+		}
+	})
+}
+
+func TestSolvabilityWildcards(t *testing.T) {
+	// Compare against an exhaustive search on every partial goal of random
+	// small puzzles.
+	rng := rand.New(rand.NewPCG(4, 5))
+	for _, dims := range [][2]int{{1, 4}, {2, 3}} {
+		rows, cols := dims[0], dims[1]
+		for range 20 {
+			values := rng.Perm(rows * cols)
+			grid := make([][]int, rows)
+			for row := range grid {
+				grid[row] = values[row*cols : (row+1)*cols]
+			}
+			p, err := NewPuzzle(grid, 0)
+			if err != nil {
+				t.Fatalf("NewPuzzle() error: %v", err)
+			}
+
+			var tiles []int
+			for val := range rows * cols {
+				if rng.IntN(2) == 0 {
+					tiles = append(tiles, val)
+				}
+			}
+			partial, err := p.PartialGoal(tiles...)
+			if err != nil {
+				t.Fatalf("PartialGoal() error: %v", err)
+			}
+			_, err = partial.Solve()
+			if got, want := partial.isSolvable(), err == nil; got != want {
+				t.Errorf("isSolvable() = %v for %v with goal tiles %v, but Solve() error = %v", got, p, tiles, err)
+			}
+		}
+	}
+}
+
+func TestSolvabilityColoredWildcards(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		grid, goal [][]int
+	}{
+		{"twin in a wildcard cell", [][]int{{1, 0}, {1, 2}}, [][]int{{1, 0}, {2, Wildcard}}},
+		{"twin in a wildcard cell, empty last", [][]int{{2, 2}, {1, 0}}, [][]int{{Wildcard, 0}, {1, 2}}},
+		{"twin in a wildcard cell, 2x3", [][]int{{2, 0, 5}, {3, 1, 3}}, [][]int{{0, 1, 5}, {2, Wildcard, 3}}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewColoredPuzzle(tt.grid, tt.goal, 0)
+			if err != nil {
+				t.Fatalf("NewColoredPuzzle() error: %v", err)
+			}
+			if !p.isSolvable() {
+				t.Errorf("isSolvable() = false for %v", p)
+			}
+		})
+	}
+
+	t.Run("random boards", func(t *testing.T) {
+		// Compare against an exhaustive search on random small boards, with
+		// few colors so that twins are common, and random wildcard cells.
+		rng := rand.New(rand.NewPCG(8, 9))
+		for _, dims := range [][2]int{{2, 2}, {2, 3}} {
+			rows, cols := dims[0], dims[1]
+			for range 200 {
+				colors := make([]int, rows*cols)
+				for i := 1; i < len(colors); i++ {
+					colors[i] = 1 + rng.IntN(rows*cols-1)
+				}
+				rng.Shuffle(len(colors), func(i, j int) { colors[i], colors[j] = colors[j], colors[i] })
+				goalColors := slices.Clone(colors)
+				rng.Shuffle(len(goalColors), func(i, j int) { goalColors[i], goalColors[j] = goalColors[j], goalColors[i] })
+				for i := range goalColors {
+					if rng.IntN(3) == 0 {
+						goalColors[i] = Wildcard
+					}
+				}
+				grid, goal := make([][]int, rows), make([][]int, rows)
+				for row := range rows {
+					grid[row] = colors[row*cols : (row+1)*cols]
+					goal[row] = goalColors[row*cols : (row+1)*cols]
+				}
+				p, err := NewColoredPuzzle(grid, goal, 0)
+				if err != nil {
+					t.Fatalf("NewColoredPuzzle() error: %v", err)
+				}
+				_, err = p.Solve()
+				if got, want := p.isSolvable(), err == nil; got != want {
+					t.Errorf("isSolvable() = %v for %v with goal %v, but Solve() error = %v", got, grid, goal, err)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,424 @@
+package slide_puzzle
+
+import "fmt"
+
+// SolveConstructive solves the puzzle the way a person would: it places tiles
+// one row at a time until two rows remain, then one column at a time until a
+// small residual board remains, which is solved optimally with breadth-first
+// search. Rows and columns are peeled from whichever side keeps the goal
+// position of the empty tile inside the residual board.
+//
+// The solution is not optimal, but it is found in polynomial time for
+// rectangular boards of any size.
+func (p Puzzle) SolveConstructive() ([]Move, error) {
+	if err := p.requireSingleEmpty(); err != nil {
+		return nil, err
+	}
+	if err := p.requireDistinct(); err != nil {
+		return nil, err
+	}
+	if err := p.requireNoBlocked(); err != nil {
+		return nil, err
+	}
+	if p.isSolved() {
+		return []Move{}, nil
+	}
+
+	s := newConstructiveSolver(p)
+	rows, cols := len(p.grid), len(p.grid[0])
+	goalEmpty := s.goal(p.emptyTile.value)
+	top, bottom, left, right := 0, rows-1, 0, cols-1
+
+	// Peel rows until two remain. A single column has no room to manoeuvre, so
+	// it is left entirely to the residual search.
+	for bottom-top+1 > 2 && right > left {
+		if goalEmpty.row != top {
+			if err := s.solveLine(s.rowCells(top, left, right), coord{row: 1}); err != nil {
+				return nil, err
+			}
+			top++
+		} else {
+			if err := s.solveLine(s.rowCells(bottom, left, right), coord{row: -1}); err != nil {
+				return nil, err
+			}
+			bottom--
+		}
+	}
+
+	// Peel columns until at most three remain.
+	for right-left+1 > 3 && bottom > top {
+		if goalEmpty.col != left {
+			if err := s.solveLine(s.colCells(left, top, bottom), coord{col: 1}); err != nil {
+				return nil, err
+			}
+			left++
+		} else {
+			if err := s.solveLine(s.colCells(right, top, bottom), coord{col: -1}); err != nil {
+				return nil, err
+			}
+			right--
+		}
+	}
+
+	if err := s.solveResidual(); err != nil {
+		return nil, err
+	}
+	return s.moves, nil
+}
+
+// constructiveSolver holds a mutable copy of a puzzle along with the cells that
+// have been solved so far.
+type constructiveSolver struct {
+	grid  [][]int
+	empty coord
+	// pos is the current position of each tile value.
+	pos   []coord
+	fixed [][]bool
+	moves []Move
+}
+
+func newConstructiveSolver(p Puzzle) *constructiveSolver {
+	rows, cols := len(p.grid), len(p.grid[0])
+	s := &constructiveSolver{
+		grid:  make([][]int, rows),
+		empty: p.emptyTile.coord,
+		pos:   make([]coord, rows*cols),
+		fixed: make([][]bool, rows),
+	}
+	for row := range p.grid {
+		s.grid[row] = make([]int, cols)
+		copy(s.grid[row], p.grid[row])
+		s.fixed[row] = make([]bool, cols)
+		for col, val := range p.grid[row] {
+			s.pos[val] = coord{row: row, col: col}
+		}
+	}
+	return s
+}
+
+// goal returns the position of val in the solved puzzle.
+func (s *constructiveSolver) goal(val int) coord {
+	cols := len(s.grid[0])
+	return coord{row: val / cols, col: val % cols}
+}
+
+func (s *constructiveSolver) goalValue(c coord) int {
+	return c.row*len(s.grid[0]) + c.col
+}
+
+func (s *constructiveSolver) rowCells(row, left, right int) []coord {
+	cells := make([]coord, 0, right-left+1)
+	for col := left; col <= right; col++ {
+		cells = append(cells, coord{row: row, col: col})
+	}
+	return cells
+}
+
+func (s *constructiveSolver) colCells(col, top, bottom int) []coord {
+	cells := make([]coord, 0, bottom-top+1)
+	for row := top; row <= bottom; row++ {
+		cells = append(cells, coord{row: row, col: col})
+	}
+	return cells
+}
+
+func (s *constructiveSolver) inBounds(c coord) bool {
+	return c.row >= 0 && c.row < len(s.grid) && c.col >= 0 && c.col < len(s.grid[0])
+}
+
+func (s *constructiveSolver) free(c coord) bool {
+	return s.inBounds(c) && !s.fixed[c.row][c.col]
+}
+
+// neighbors returns the free cells adjacent to c.
+func (s *constructiveSolver) neighbors(c coord) []coord {
+	var result []coord
+	for _, d := range []coord{{row: -1}, {row: 1}, {col: -1}, {col: 1}} {
+		n := coord{row: c.row + d.row, col: c.col + d.col}
+		if s.free(n) {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// slideFrom moves the tile at src, which must be adjacent to the empty space,
+// into the empty space.
+func (s *constructiveSolver) slideFrom(src coord) {
+	var m Move
+	for _, candidate := range allMoves {
+		if candidate.source(s.empty) == src {
+			m = candidate
+			break
+		}
+	}
+	val := s.grid[src.row][src.col]
+	emptyVal := s.grid[s.empty.row][s.empty.col]
+	s.grid[s.empty.row][s.empty.col] = val
+	s.grid[src.row][src.col] = emptyVal
+	s.pos[val] = s.empty
+	s.pos[emptyVal] = src
+	s.empty = src
+	s.moves = append(s.moves, m)
+}
+
+// path returns the shortest path of free cells from start to end, excluding
+// start, that does not pass through avoid. It returns false if there is none.
+func (s *constructiveSolver) path(start, end, avoid coord) ([]coord, bool) {
+	if start == end {
+		return nil, true
+	}
+	prev := map[coord]coord{start: start}
+	queue := []coord{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, n := range s.neighbors(current) {
+			if _, seen := prev[n]; seen || n == avoid {
+				continue
+			}
+			prev[n] = current
+			if n == end {
+				var result []coord
+				for c := end; c != start; c = prev[c] {
+					result = append(result, c)
+				}
+				for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+					result[i], result[j] = result[j], result[i]
+				}
+				return result, true
+			}
+			queue = append(queue, n)
+		}
+	}
+	return nil, false
+}
+
+// moveEmptyTo moves the empty space to target without disturbing avoid.
+func (s *constructiveSolver) moveEmptyTo(target, avoid coord) bool {
+	steps, ok := s.path(s.empty, target, avoid)
+	if !ok {
+		return false
+	}
+	for _, step := range steps {
+		s.slideFrom(step)
+	}
+	return true
+}
+
+// moveTileTo moves the tile with the given value to target using only free
+// cells.
+func (s *constructiveSolver) moveTileTo(val int, target coord) error {
+	// Nudge the tile along its shortest path, bringing the empty space around
+	// in front of it before each step.
+	steps, ok := s.path(s.pos[val], target, coord{row: -1, col: -1})
+	if !ok {
+		return fmt.Errorf("constructive solver: no path for tile %d to %v", val, target)
+	}
+	for _, step := range steps {
+		if !s.moveEmptyTo(step, s.pos[val]) {
+			// The empty space is boxed in behind the tile; fall back to a
+			// search over the joint positions of the tile and empty space.
+			return s.moveTileJointly(val, target)
+		}
+		s.slideFrom(s.pos[val])
+	}
+	return nil
+}
+
+// moveTileJointly moves the tile with the given value to target by
+// breadth-first search over the positions of both the tile and the empty
+// space. It is slower than moveTileTo but always succeeds when possible.
+func (s *constructiveSolver) moveTileJointly(val int, target coord) error {
+	type state struct{ tile, empty coord }
+	start := state{tile: s.pos[val], empty: s.empty}
+	prev := map[state]state{start: start}
+	queue := []state{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current.tile == target {
+			// Replay the empty space's path from the start state.
+			var path []coord
+			for st := current; st != start; st = prev[st] {
+				path = append(path, st.empty)
+			}
+			for i := len(path) - 1; i >= 0; i-- {
+				s.slideFrom(path[i])
+			}
+			return nil
+		}
+		for _, n := range s.neighbors(current.empty) {
+			next := state{tile: current.tile, empty: n}
+			if n == current.tile {
+				next.tile = current.empty
+			}
+			if _, seen := prev[next]; !seen {
+				prev[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
+	return fmt.Errorf("constructive solver: tile %d cannot reach %v", val, target)
+}
+
+func (s *constructiveSolver) fix(cells ...coord) {
+	for _, c := range cells {
+		s.fixed[c.row][c.col] = true
+	}
+}
+
+func (s *constructiveSolver) unfix(cells ...coord) {
+	for _, c := range cells {
+		s.fixed[c.row][c.col] = false
+	}
+}
+
+// solveLine places the goal tiles of cells, an edge row or column of the
+// unsolved region ending in a corner of that region, and marks them fixed.
+// inward is the direction from the line into the rest of the region.
+func (s *constructiveSolver) solveLine(cells []coord, inward coord) error {
+	n := len(cells)
+	for _, c := range cells[:n-2] {
+		if err := s.moveTileTo(s.goalValue(c), c); err != nil {
+			return err
+		}
+		s.fix(c)
+	}
+
+	// The last two tiles cannot be placed one after the other without
+	// disturbing the first. Instead, stage the second-to-last tile in the
+	// corner and the last tile just inside it, then rotate both into place.
+	t1, t2 := cells[n-2], cells[n-1]
+	v1, v2 := s.goalValue(t1), s.goalValue(t2)
+	if s.pos[v1] == t1 && s.pos[v2] == t2 {
+		s.fix(t1, t2)
+		return nil
+	}
+
+	inside := coord{row: t2.row + inward.row, col: t2.col + inward.col}
+	if err := s.moveTileTo(v1, t2); err != nil {
+		return err
+	}
+	s.fix(t2)
+
+	// With the corner fixed, t1 is a dead end. If the last tile is in it, or
+	// blocking its only exit with the empty space inside, it can never get out.
+	// Park the last tile further inside while placing the corner again.
+	below := coord{row: t1.row + inward.row, col: t1.col + inward.col}
+	if s.pos[v2] == t1 || (s.pos[v2] == below && s.empty == t1) {
+		s.unfix(t2)
+		park := coord{row: below.row + inward.row, col: below.col + inward.col}
+		if err := s.moveTileTo(v2, park); err != nil {
+			return err
+		}
+		s.fix(park)
+		if err := s.moveTileTo(v1, t2); err != nil {
+			return err
+		}
+		s.unfix(park)
+		s.fix(t2)
+	}
+
+	if err := s.moveTileTo(v2, inside); err != nil {
+		return err
+	}
+	s.fix(inside)
+	if !s.moveEmptyTo(t1, coord{row: -1, col: -1}) {
+		return fmt.Errorf("constructive solver: empty space cannot reach %v", t1)
+	}
+	s.unfix(t2, inside)
+	s.slideFrom(t2)
+	s.slideFrom(inside)
+	s.fix(t1, t2)
+	return nil
+}
+
+// solveResidual solves the unfixed part of the board with breadth-first
+// search. The residual board is small enough that this is fast.
+func (s *constructiveSolver) solveResidual() error {
+	var cells []coord
+	for row := range s.grid {
+		for col := range s.grid[row] {
+			if !s.fixed[row][col] {
+				cells = append(cells, coord{row: row, col: col})
+			}
+		}
+	}
+
+	// key captures the contents of the residual cells.
+	key := func() string {
+		b := make([]byte, 0, len(cells)*4)
+		for _, c := range cells {
+			b = fmt.Appendf(b, "%d,", s.grid[c.row][c.col])
+		}
+		return string(b)
+	}
+	solved := func() bool {
+		for _, c := range cells {
+			if s.grid[c.row][c.col] != s.goalValue(c) {
+				return false
+			}
+		}
+		return true
+	}
+	if solved() {
+		return nil
+	}
+
+	// Search from a snapshot of the solver, replaying move sequences to
+	// reach each state. The residual board has at most a few hundred states.
+	type state struct {
+		moves []coord
+	}
+	base := *s
+	base.grid = cloneGrid(s.grid)
+	base.pos = append([]coord(nil), s.pos...)
+	restore := func() {
+		s.grid = cloneGrid(base.grid)
+		s.pos = append(s.pos[:0], base.pos...)
+		s.empty = base.empty
+		s.moves = base.moves
+	}
+
+	visited := map[string]bool{key(): true}
+	queue := []state{{}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		restore()
+		for _, c := range current.moves {
+			s.slideFrom(c)
+		}
+		at := s.empty
+		for _, n := range s.neighbors(at) {
+			s.slideFrom(n)
+			if solved() {
+				return nil
+			}
+			if k := key(); !visited[k] {
+				visited[k] = true
+				next := make([]coord, len(current.moves)+1)
+				copy(next, current.moves)
+				next[len(current.moves)] = n
+				queue = append(queue, state{moves: next})
+			}
+			// Undo the move and drop it from the solution.
+			s.slideFrom(at)
+			s.moves = s.moves[:len(s.moves)-2]
+		}
+	}
+
+	restore()
+	return UnsolvablePuzzleError{}
+}
+
+func cloneGrid(grid [][]int) [][]int {
+	clone := make([][]int, len(grid))
+	for i := range grid {
+		clone[i] = make([]int, len(grid[i]))
+		copy(clone[i], grid[i])
+	}
+	return clone
+}
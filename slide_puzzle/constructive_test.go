@@ -0,0 +1,82 @@
+package slide_puzzle
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+)
+
+// assertSolves fails the test unless applying moves to p solves it.
+func assertSolves(t *testing.T, p Puzzle, moves []Move) {
+	t.Helper()
+	result := p
+	for i, move := range moves {
+		var err error
+		result, err = result.makeMove(move)
+		if err != nil {
+			t.Fatalf("applying move %d (%v) failed: %v", i, move, err)
+		}
+	}
+	if !result.isSolved() {
+		t.Fatalf("puzzle not solved after applying %d moves: %v", len(moves), result)
+	}
+}
+
+func TestSolveConstructive(t *testing.T) {
+	sizes := []struct {
+		rows, cols int
+	}{
+		{1, 5}, {5, 1}, {2, 2}, {2, 3}, {3, 2}, {3, 3}, {2, 8}, {8, 2},
+		{4, 4}, {3, 7}, {7, 3}, {10, 10}, {20, 30},
+	}
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	for _, size := range sizes {
+		// Try the empty tile's goal position in each corner and the middle.
+		n := size.rows * size.cols
+		for _, empty := range []int{0, size.cols - 1, n - size.cols, n - 1, n / 2} {
+			name := fmt.Sprintf("%dx%d empty=%d", size.rows, size.cols, empty)
+			t.Run(name, func(t *testing.T) {
+				puzzle, err := Scramble(size.rows, size.cols, empty, 20*n, rng)
+				if err != nil {
+					t.Fatalf("Scramble() error: %v", err)
+				}
+
+				got, err := puzzle.SolveConstructive()
+				if err != nil {
+					t.Fatalf("SolveConstructive() error: %v", err)
+				}
+				assertSolves(t, *puzzle, got)
+			})
+		}
+	}
+
+	t.Run("already solved puzzle", func(t *testing.T) {
+		puzzle, err := NewPuzzle(goalGrid(4, 4), 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		got, err := puzzle.SolveConstructive()
+		if err != nil {
+			t.Fatalf("SolveConstructive() error: %v", err)
+		}
+		if len(got) != 0 {
+			t.Fatalf("SolveConstructive() returned %d moves, want 0", len(got))
+		}
+	})
+
+	t.Run("unsolvable puzzle returns error", func(t *testing.T) {
+		grid := goalGrid(4, 4)
+		grid[3][2], grid[3][3] = grid[3][3], grid[3][2]
+		puzzle, err := NewPuzzle(grid, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+
+		_, err = puzzle.SolveConstructive()
+		if !errors.As(err, &UnsolvablePuzzleError{}) {
+			t.Fatalf("SolveConstructive() error = %v, want UnsolvablePuzzleError", err)
+		}
+	})
+}
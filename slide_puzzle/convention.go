@@ -0,0 +1,108 @@
+package slide_puzzle
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Convention says what a move direction describes. Move values always use
+// TileMoves; other conventions only matter when moves are parsed, printed or
+// drawn.
+type Convention int
+
+const (
+	// TileMoves names the direction the tile moves: North slides the tile
+	// below the empty space up.
+	TileMoves Convention = iota
+	// BlankMoves names the direction the empty space moves, as in much of
+	// the puzzle literature: North (or Up) moves the empty space up, sliding
+	// the tile above it down.
+	BlankMoves
+)
+
+var conventionStrings = map[Convention]string{
+	TileMoves:  "tile",
+	BlankMoves: "blank",
+}
+
+func (c Convention) String() string {
+	return conventionStrings[c]
+}
+
+// ParseConvention parses the name of a convention as returned by String.
+func ParseConvention(s string) (Convention, error) {
+	for c, name := range conventionStrings {
+		if strings.EqualFold(s, name) {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown move convention %q; want tile or blank", s)
+}
+
+// ConvertMoves returns moves, written in convention from, rewritten in
+// convention to. The two conventions name opposite directions for the same
+// move.
+func ConvertMoves(moves []Move, from, to Convention) []Move {
+	converted := make([]Move, len(moves))
+	for i, m := range moves {
+		converted[i] = m.Convert(from, to)
+	}
+	return converted
+}
+
+// Convert returns m, written in convention from, rewritten in convention to.
+func (m Move) Convert(from, to Convention) Move {
+	if from == to {
+		return m
+	}
+	return m.opposite()
+}
+
+// moveNames maps the accepted spellings of each direction to the move. Single
+// letters are matched separately, so that runs of them like "RDLU" can be
+// parsed.
+var moveNames = map[string]Move{
+	"north": North, "up": North,
+	"south": South, "down": South,
+	"east": East, "right": East,
+	"west": West, "left": West,
+}
+
+var moveLetters = map[rune]Move{
+	'n': North, 'u': North,
+	's': South, 'd': South,
+	'e': East, 'r': East,
+	'w': West, 'l': West,
+}
+
+// ParseMoves parses a list of moves written in convention c, and returns them
+// in the TileMoves convention used by Move values. Moves are separated by
+// commas or spaces, and are compass directions (North) or screen directions
+// (Up), case-insensitively; single-letter forms (N or U) may be run together,
+// as in "RDLU".
+func ParseMoves(s string, c Convention) ([]Move, error) {
+	moves := []Move{}
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' || r == '\n' }) {
+		if m, ok := moveNames[strings.ToLower(field)]; ok {
+			moves = append(moves, m.Convert(c, TileMoves))
+			continue
+		}
+		for _, r := range strings.ToLower(field) {
+			m, ok := moveLetters[r]
+			if !ok {
+				return nil, &InvalidMoveError{fmt.Sprintf("unknown move %q", field)}
+			}
+			moves = append(moves, m.Convert(c, TileMoves))
+		}
+	}
+	return moves, nil
+}
+
+// FormatMoves writes moves in convention c, separated by commas.
+func FormatMoves(moves []Move, c Convention) string {
+	names := make([]string, len(moves))
+	for i, m := range moves {
+		names[i] = m.Convert(TileMoves, c).String()
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,103 @@
+package slide_puzzle
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConvertMoves(t *testing.T) {
+	moves := []Move{North, East, South, West}
+	blank := ConvertMoves(moves, TileMoves, BlankMoves)
+	if diff := cmp.Diff([]Move{South, West, North, East}, blank); diff != "" {
+		t.Errorf("ConvertMoves() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(moves, ConvertMoves(blank, BlankMoves, TileMoves)); diff != "" {
+		t.Errorf("ConvertMoves() round trip mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(moves, ConvertMoves(moves, BlankMoves, BlankMoves)); diff != "" {
+		t.Errorf("ConvertMoves() to the same convention mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseConvention(t *testing.T) {
+	for _, c := range []Convention{TileMoves, BlankMoves} {
+		got, err := ParseConvention(strings.ToUpper(c.String()))
+		if err != nil {
+			t.Fatalf("ParseConvention(%q) error: %v", c, err)
+		}
+		if got != c {
+			t.Errorf("ParseConvention(%q) = %v, want %v", c, got, c)
+		}
+	}
+	if _, err := ParseConvention("empty"); err == nil {
+		t.Errorf("ParseConvention(\"empty\") error = nil, want error")
+	}
+}
+
+func TestParseMoves(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		convention Convention
+		want       []Move
+	}{
+		{name: "compass names", input: "North, east,WEST", convention: TileMoves, want: []Move{North, East, West}},
+		{name: "screen names", input: "up down left right", convention: TileMoves, want: []Move{North, South, West, East}},
+		{name: "run of letters", input: "RDLU", convention: TileMoves, want: []Move{East, South, West, North}},
+		{name: "blank convention", input: "RDLU", convention: BlankMoves, want: []Move{West, North, East, South}},
+		{name: "empty", input: "", convention: TileMoves, want: []Move{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMoves(tt.input, tt.convention)
+			if err != nil {
+				t.Fatalf("ParseMoves() error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseMoves() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("invalid move returns error", func(t *testing.T) {
+		_, err := ParseMoves("North, sideways", TileMoves)
+		var moveErr *InvalidMoveError
+		if !errors.As(err, &moveErr) {
+			t.Errorf("ParseMoves() error type = %T, want *InvalidMoveError", err)
+		}
+	})
+
+	t.Run("round trip through FormatMoves", func(t *testing.T) {
+		moves := []Move{North, North, West, South, East}
+		for _, c := range []Convention{TileMoves, BlankMoves} {
+			got, err := ParseMoves(FormatMoves(moves, c), c)
+			if err != nil {
+				t.Fatalf("ParseMoves() error: %v", err)
+			}
+			if diff := cmp.Diff(moves, got); diff != "" {
+				t.Errorf("ParseMoves(FormatMoves(%v)) mismatch (-want +got):\n%s", c, diff)
+			}
+		}
+	})
+}
+
+func TestWriteSolutionSVGConvention(t *testing.T) {
+	p, err := NewPuzzle([][]int{{1, 0, 2}, {3, 4, 5}}, 0)
+	if err != nil {
+		t.Fatalf("NewPuzzle() error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSolutionSVG(&buf, *p, []Move{East}, SVGOptions{Convention: BlankMoves}); err != nil {
+		t.Fatalf("WriteSolutionSVG() error: %v", err)
+	}
+	// The empty space moves West when tile 1 moves East.
+	if !strings.Contains(buf.String(), ">1. West<") {
+		t.Errorf("WriteSolutionSVG() does not label the move as West:\n%s", buf.String())
+	}
+}
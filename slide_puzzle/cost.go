@@ -0,0 +1,78 @@
+package slide_puzzle
+
+import (
+	"fmt"
+	"math"
+)
+
+// TileCosts gives the cost of moving each tile value one step, for puzzles
+// where some tiles are harder to move than others. Tiles not listed cost 1.
+type TileCosts map[int]int
+
+// cost returns the cost of moving the tile with value val.
+func (c TileCosts) cost(val int) int {
+	if cost, ok := c[val]; ok {
+		return cost
+	}
+	return 1
+}
+
+// distance returns the Manhattan distance with each tile's distance scaled by
+// its cost. Each move changes the distance of one tile by one step at that
+// tile's cost, so like the Manhattan distance it never overestimates.
+func (c TileCosts) distance(p Puzzle) int {
+	if len(c) == 0 {
+		return manhattanDistance(p)
+	}
+	cols := len(p.grid[0])
+	total := 0
+	for row := range p.grid {
+		for col, val := range p.grid[row] {
+			if val == p.emptyTile.value || val == Blocked {
+				continue
+			}
+			total += c.cost(val) * (abs(row-val/cols) + abs(col-val%cols))
+		}
+	}
+	return total
+}
+
+// CostSolution is a solution that minimizes the total cost of the moves made
+// rather than their number.
+type CostSolution struct {
+	Moves []Move
+	// Cost is the total cost of Moves.
+	Cost int
+	// LowerBound is a proven lower bound on the cost of an optimal solution.
+	LowerBound int
+	// Suboptimality is Cost / LowerBound, i.e. the solution is proven to cost
+	// at most this many times as much as an optimal one. It is 1 when the
+	// solution is proven optimal.
+	Suboptimality float64
+}
+
+// SolveCost finds a solution whose total cost is at most (1+epsilon) times the
+// optimal cost, using weighted A* with the cost-scaled Manhattan distance
+// heuristic. An epsilon of 0 gives a solution of minimal cost, which may take
+// more moves than the shortest solution.
+func (p Puzzle) SolveCost(costs TileCosts, epsilon float64) (CostSolution, error) {
+	if epsilon < 0 || math.IsNaN(epsilon) || math.IsInf(epsilon, 0) {
+		return CostSolution{}, fmt.Errorf("epsilon must be a non-negative number; got %v", epsilon)
+	}
+	if len(costs) > 0 {
+		// Costs are given per tile value, which colored tiles do not keep.
+		if err := p.requireDistinct(); err != nil {
+			return CostSolution{}, err
+		}
+	}
+	numTiles := len(p.grid) * len(p.grid[0])
+	for val, cost := range costs {
+		if val < 0 || val >= numTiles {
+			return CostSolution{}, &InvalidPuzzleError{fmt.Sprintf("tile costs must be for values in range [0, %d); got %d", numTiles, val)}
+		}
+		if cost <= 0 {
+			return CostSolution{}, &InvalidPuzzleError{fmt.Sprintf("cost of tile %d must be positive; got %d", val, cost)}
+		}
+	}
+	return p.weightedAStar(1+epsilon, costs)
+}
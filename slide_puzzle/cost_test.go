@@ -0,0 +1,131 @@
+package slide_puzzle
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+// cheapestCost finds the optimal solution cost by Dijkstra's algorithm, as a
+// reference for SolveCost.
+func cheapestCost(t *testing.T, start Puzzle, costs TileCosts) int {
+	t.Helper()
+	dist := map[string]int{start.String(): 0}
+	puzzles := map[string]Puzzle{start.String(): start}
+	done := map[string]bool{}
+	for {
+		key, best := "", math.MaxInt
+		for k, d := range dist {
+			if !done[k] && d < best {
+				key, best = k, d
+			}
+		}
+		if key == "" {
+			t.Fatalf("no solution found for %v", start)
+		}
+		p := puzzles[key]
+		if p.isSolved() {
+			return best
+		}
+		done[key] = true
+		for move := range p.getMoves() {
+			next, err := p.makeMove(move)
+			if err != nil {
+				t.Fatalf("makeMove() error: %v", err)
+			}
+			empty := p.emptyTile.coord
+			d := best + costs.cost(next.grid[empty.row][empty.col])
+			if old, ok := dist[next.String()]; !ok || d < old {
+				dist[next.String()] = d
+				puzzles[next.String()] = next
+			}
+		}
+	}
+}
+
+// movesCost returns the total cost of applying moves to p.
+func movesCost(t *testing.T, p Puzzle, moves []Move, costs TileCosts) int {
+	t.Helper()
+	total := 0
+	for _, move := range moves {
+		src := move.source(p.emptyTile.coord)
+		total += costs.cost(p.grid[src.row][src.col])
+		var err error
+		if p, err = p.makeMove(move); err != nil {
+			t.Fatalf("makeMove() error: %v", err)
+		}
+	}
+	return total
+}
+
+func TestSolveCost(t *testing.T) {
+	costs := TileCosts{1: 5, 4: 3}
+	rng := rand.New(rand.NewPCG(6, 1))
+	for range 10 {
+		p, err := Scramble(2, 3, 0, 30, rng)
+		if err != nil {
+			t.Fatalf("Scramble() error: %v", err)
+		}
+
+		t.Run("optimal cost", func(t *testing.T) {
+			got, err := p.SolveCost(costs, 0)
+			if err != nil {
+				t.Fatalf("SolveCost() error: %v", err)
+			}
+			assertSolves(t, *p, got.Moves)
+			if want := cheapestCost(t, *p, costs); got.Cost != want {
+				t.Errorf("SolveCost(%v).Cost = %d, want %d", p, got.Cost, want)
+			}
+			if c := movesCost(t, *p, got.Moves, costs); c != got.Cost {
+				t.Errorf("SolveCost(%v).Cost = %d, but moves cost %d", p, got.Cost, c)
+			}
+			if got.LowerBound != got.Cost || got.Suboptimality != 1 {
+				t.Errorf("SolveCost(%v) = %+v, want proven optimal", p, got)
+			}
+		})
+
+		t.Run("bounded cost", func(t *testing.T) {
+			got, err := p.SolveCost(costs, 1)
+			if err != nil {
+				t.Fatalf("SolveCost() error: %v", err)
+			}
+			assertSolves(t, *p, got.Moves)
+			if want := cheapestCost(t, *p, costs); got.Cost > 2*want || got.LowerBound > want {
+				t.Errorf("SolveCost(%v) = %+v, optimal cost is %d", p, got, want)
+			}
+		})
+	}
+
+	t.Run("unit costs give shortest solution", func(t *testing.T) {
+		p, err := Scramble(2, 4, 0, 40, rand.New(rand.NewPCG(1, 1)))
+		if err != nil {
+			t.Fatalf("Scramble() error: %v", err)
+		}
+		want, err := p.Solve()
+		if err != nil {
+			t.Fatalf("Solve() error: %v", err)
+		}
+		got, err := p.SolveCost(nil, 0)
+		if err != nil {
+			t.Fatalf("SolveCost() error: %v", err)
+		}
+		if got.Cost != len(want) || len(got.Moves) != len(want) {
+			t.Errorf("SolveCost(nil) = %+v, want cost and length %d", got, len(want))
+		}
+	})
+
+	t.Run("invalid costs return error", func(t *testing.T) {
+		p, err := Goal(2, 3, 0)
+		if err != nil {
+			t.Fatalf("Goal() error: %v", err)
+		}
+		for _, costs := range []TileCosts{{1: 0}, {2: -1}, {6: 2}} {
+			_, err := p.SolveCost(costs, 0)
+			var invalidErr *InvalidPuzzleError
+			if !errors.As(err, &invalidErr) {
+				t.Errorf("SolveCost(%v) error type = %T, want *InvalidPuzzleError", costs, err)
+			}
+		}
+	})
+}
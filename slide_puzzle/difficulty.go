@@ -0,0 +1,219 @@
+package slide_puzzle
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+)
+
+// Tier is a coarse difficulty level for players.
+type Tier int
+
+const (
+	Easy Tier = iota
+	Medium
+	Hard
+	Expert
+)
+
+var tierStrings = map[Tier]string{
+	Easy:   "easy",
+	Medium: "medium",
+	Hard:   "hard",
+	Expert: "expert",
+}
+
+func (t Tier) String() string {
+	return tierStrings[t]
+}
+
+// ParseTier parses the name of a tier as returned by String.
+func ParseTier(s string) (Tier, error) {
+	for t, name := range tierStrings {
+		if strings.EqualFold(s, name) {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown difficulty tier %q; want easy, medium, hard or expert", s)
+}
+
+// tierScores are the lowest scores of Medium, Hard and Expert puzzles.
+var tierScores = []float64{10, 20, 30}
+
+// rateNodeLimit is the number of states Rate visits before settling for a
+// lower bound on the solution length.
+const rateNodeLimit = 5_000_000
+
+// Difficulty describes how hard a puzzle is to solve.
+type Difficulty struct {
+	// Length is the length of an optimal solution, or if Exact is false, a
+	// lower bound on it.
+	Length int
+	Exact  bool
+	// Solutions is the number of different optimal solutions. It is a lower
+	// bound if the search ran out of time while counting, and 0 if Exact is
+	// false.
+	Solutions int
+	// HeuristicGap is how many more moves than the Manhattan distance the
+	// puzzle takes. Large gaps mean detours that the obvious greedy moves do
+	// not find.
+	HeuristicGap int
+	// Branching is the effective branching factor of the search that found
+	// Length: the b for which a full tree of that depth has as many nodes as
+	// were searched. Higher values mean more promising-looking dead ends.
+	Branching float64
+	// Score combines the above into a single number, growing with Length,
+	// HeuristicGap and Branching and shrinking as Solutions grows.
+	Score float64
+	Tier  Tier
+}
+
+// Rate estimates the difficulty of p. It searches for every optimal solution
+// with iterative deepening, so it takes longer than SolveIDAStar; on boards
+// where that takes too long, Length is a lower bound instead. Like
+// SolveIDAStar, it rejects blocked boards whose Solvability is
+// SolvabilityUnknown.
+func (p Puzzle) Rate() (Difficulty, error) {
+	if err := p.requireSingleEmpty(); err != nil {
+		return Difficulty{}, err
+	}
+	if err := p.requireDistinct(); err != nil {
+		return Difficulty{}, err
+	}
+	if !p.isSolvable() {
+		return Difficulty{}, UnsolvablePuzzleError{}
+	}
+	if p.Solvability() == SolvabilityUnknown {
+		// The search has no duplicate detection, so it would never finish on
+		// an unsolvable puzzle.
+		return Difficulty{}, &InvalidPuzzleError{"cannot tell whether this blocked board is solvable, so it cannot be rated"}
+	}
+
+	r := &rater{board: newFlatPuzzle(p)}
+	d := Difficulty{}
+	var iterationNodes int
+	for threshold := r.board.h; ; threshold = r.next {
+		r.threshold, r.next, r.solutions = threshold, math.MaxInt, 0
+		before := r.nodes
+		r.search(0, -1)
+		iterationNodes = r.nodes - before
+		d.Length = threshold
+		if r.solutions > 0 {
+			d.Exact, d.Solutions = true, r.solutions
+			break
+		}
+		if r.nodes >= rateNodeLimit {
+			break
+		}
+		if r.next == math.MaxInt {
+			// Only possible when blocked cells left solvability unknown.
+			return Difficulty{}, UnsolvablePuzzleError{}
+		}
+	}
+
+	d.HeuristicGap = d.Length - manhattanDistance(p)
+	d.Branching = effectiveBranching(iterationNodes, d.Length)
+	d.Score = float64(d.Length) + float64(d.HeuristicGap)/2 + 5*max(d.Branching-1, 0) -
+		math.Log2(float64(max(d.Solutions, 1)))
+	d.Score = max(d.Score, 0)
+	for _, score := range tierScores {
+		if d.Score >= score {
+			d.Tier++
+		}
+	}
+	return d, nil
+}
+
+// rater counts the optimal solutions of a puzzle with iterative deepening A*.
+type rater struct {
+	board                *flatPuzzle
+	threshold, solutions int
+	// next is the smallest estimate that exceeded threshold.
+	next  int
+	nodes int
+}
+
+// search counts the solutions within the threshold below the current state,
+// reached in g moves, the last of which was last (or -1 for none).
+func (r *rater) search(g int, last Move) {
+	if r.nodes >= rateNodeLimit {
+		return
+	}
+	r.nodes++
+	if f := g + r.board.h; f > r.threshold {
+		r.next = min(r.next, f)
+		return
+	}
+	if r.board.h == 0 {
+		r.solutions++
+		return
+	}
+	for _, m := range allMoves {
+		if last >= 0 && m == last.opposite() {
+			continue
+		}
+		src := r.board.source(m)
+		if src < 0 {
+			continue
+		}
+		empty := r.board.empty
+		r.board.apply(src)
+		r.search(g+1, m)
+		r.board.apply(empty)
+	}
+}
+
+// effectiveBranching returns the b for which a tree of the given depth, with b
+// children per node, has the given number of nodes.
+func effectiveBranching(nodes, depth int) float64 {
+	if depth == 0 || nodes <= depth+1 {
+		return 1
+	}
+	size := func(b float64) float64 {
+		total, level := 1.0, 1.0
+		for range depth {
+			level *= b
+			total += level
+		}
+		return total
+	}
+	lo, hi := 1.0, 4.0
+	for range 50 {
+		mid := (lo + hi) / 2
+		if size(mid) < float64(nodes) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// ScrambleTier returns a random puzzle of the given tier, made like Scramble
+// by random moves from the goal state, along with its rating. The number of
+// moves is adjusted until a puzzle of the right tier turns up, giving up after
+// a few hundred attempts, e.g. because the board is too small for the tier.
+func ScrambleTier(rows, cols, emptyTileValue int, tier Tier, rng *rand.Rand) (*Puzzle, Difficulty, error) {
+	const attempts = 300
+	moves := 10
+	for range attempts {
+		p, err := Scramble(rows, cols, emptyTileValue, moves, rng)
+		if err != nil {
+			return nil, Difficulty{}, err
+		}
+		d, err := p.Rate()
+		if err != nil {
+			return nil, Difficulty{}, err
+		}
+		switch {
+		case d.Tier < tier:
+			moves = min(moves+max(1, moves/4), 1000)
+		case d.Tier > tier:
+			moves = max(1, moves-max(1, moves/4))
+		default:
+			return p, d, nil
+		}
+	}
+	return nil, Difficulty{}, fmt.Errorf("could not generate a %s puzzle on a %dx%d board in %d attempts", tier, rows, cols, attempts)
+}
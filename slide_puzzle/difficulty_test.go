@@ -0,0 +1,160 @@
+package slide_puzzle
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+// countOptimalSolutions counts the shortest paths from p to the goal with a
+// breadth-first search that tracks how many shortest paths reach each state.
+func countOptimalSolutions(t *testing.T, p Puzzle) (length, count int) {
+	t.Helper()
+	paths := map[string]int{p.String(): 1}
+	layer := []Puzzle{p}
+	for depth := 0; len(layer) > 0; depth++ {
+		for _, current := range layer {
+			if current.isSolved() {
+				count += paths[current.String()]
+			}
+		}
+		if count > 0 {
+			return depth, count
+		}
+
+		nextPaths := map[string]int{}
+		var next []Puzzle
+		for _, current := range layer {
+			for _, m := range allMoves {
+				if !current.getMoves()[m] {
+					continue
+				}
+				child, err := current.makeMove(m)
+				if err != nil {
+					t.Fatalf("makeMove() error: %v", err)
+				}
+				key := child.String()
+				if _, seen := paths[key]; seen {
+					continue
+				}
+				if _, queued := nextPaths[key]; !queued {
+					next = append(next, child)
+				}
+				nextPaths[key] += paths[current.String()]
+			}
+		}
+		for key, n := range nextPaths {
+			paths[key] = n
+		}
+		layer = next
+	}
+	t.Fatalf("%v is not solvable", p)
+	return 0, 0
+}
+
+func TestRate(t *testing.T) {
+	t.Run("solved puzzle", func(t *testing.T) {
+		p, err := Goal(3, 3, 0)
+		if err != nil {
+			t.Fatalf("Goal() error: %v", err)
+		}
+		d, err := p.Rate()
+		if err != nil {
+			t.Fatalf("Rate() error: %v", err)
+		}
+		if d.Length != 0 || !d.Exact || d.Solutions != 1 || d.Tier != Easy {
+			t.Errorf("Rate() = %+v, want an exact easy rating of length 0 with 1 solution", d)
+		}
+	})
+
+	t.Run("matches exhaustive search", func(t *testing.T) {
+		rng := rand.New(rand.NewPCG(4, 7))
+		for _, moves := range []int{4, 12, 18, 30} {
+			p, err := Scramble(3, 3, 0, moves, rng)
+			if err != nil {
+				t.Fatalf("Scramble() error: %v", err)
+			}
+			d, err := p.Rate()
+			if err != nil {
+				t.Fatalf("Rate() error: %v", err)
+			}
+			length, count := countOptimalSolutions(t, *p)
+			if d.Length != length || d.Solutions != count || !d.Exact {
+				t.Errorf("Rate() = %+v for %v, want exact length %d with %d solutions", d, p, length, count)
+			}
+			if want := length - manhattanDistance(*p); d.HeuristicGap != want {
+				t.Errorf("Rate().HeuristicGap = %d for %v, want %d", d.HeuristicGap, p, want)
+			}
+		}
+	})
+
+	t.Run("unsolvable puzzle returns error", func(t *testing.T) {
+		p, err := NewPuzzle([][]int{{0, 2, 1}, {3, 4, 5}}, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		if _, err := p.Rate(); err == nil {
+			t.Errorf("Rate() error = nil, want UnsolvablePuzzleError")
+		}
+	})
+
+	t.Run("blocked board of unknown solvability returns error", func(t *testing.T) {
+		p, err := NewPuzzle([][]int{{3, 4, Blocked, 9, 0}, {1, 8, 6, 5, 7}}, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		if got := p.Solvability(); got != SolvabilityUnknown {
+			t.Fatalf("Solvability() = %v, want %v", got, SolvabilityUnknown)
+		}
+		done := make(chan error, 1)
+		go func() {
+			_, err := p.Rate()
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			var invalidErr *InvalidPuzzleError
+			if !errors.As(err, &invalidErr) {
+				t.Errorf("Rate() error type = %T, want *InvalidPuzzleError", err)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("Rate() did not return")
+		}
+	})
+}
+
+func TestScrambleTier(t *testing.T) {
+	rng := rand.New(rand.NewPCG(4, 8))
+	for _, tier := range []Tier{Easy, Medium, Hard} {
+		t.Run(tier.String(), func(t *testing.T) {
+			p, d, err := ScrambleTier(3, 3, 0, tier, rng)
+			if err != nil {
+				t.Fatalf("ScrambleTier() error: %v", err)
+			}
+			if d.Tier != tier {
+				t.Errorf("ScrambleTier() rating = %+v, want tier %v", d, tier)
+			}
+			if got, err := p.Rate(); err != nil || got != d {
+				t.Errorf("Rate() = %+v, %v; want %+v", got, err, d)
+			}
+		})
+	}
+
+	t.Run("board too small", func(t *testing.T) {
+		if _, _, err := ScrambleTier(2, 2, 0, Expert, rng); err == nil {
+			t.Errorf("ScrambleTier() error = nil, want error for an expert 2x2 puzzle")
+		}
+	})
+}
+
+func TestParseTier(t *testing.T) {
+	for _, tier := range []Tier{Easy, Medium, Hard, Expert} {
+		if got, err := ParseTier(tier.String()); err != nil || got != tier {
+			t.Errorf("ParseTier(%q) = %v, %v; want %v", tier, got, err, tier)
+		}
+	}
+	if _, err := ParseTier("impossible"); err == nil {
+		t.Errorf("ParseTier(\"impossible\") error = nil, want error")
+	}
+}
@@ -0,0 +1,528 @@
+package slide_puzzle
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// EnumerateOptions controls EnumerateStates.
+type EnumerateOptions struct {
+	// Tiles, if not empty, lists the tiles to tell apart. All other tiles
+	// except the empty one are treated as identical, which shrinks the state
+	// space to the arrangements of just these tiles.
+	Tiles []int
+	// InMemory keeps layers in memory instead of writing them to disk.
+	InMemory bool
+	// TempDir is the directory in which layer files are created. If empty,
+	// the system temporary directory is used.
+	TempDir string
+	// ChunkSize is the number of states that are sorted in memory at once
+	// before being written out as a sorted run. If zero, 1<<20 is used.
+	ChunkSize int
+	// Antipodes is the number of states at the greatest distance to return
+	// in Enumeration.Antipodes. It is ignored if Tiles is set.
+	Antipodes int
+}
+
+// Enumeration is the result of EnumerateStates.
+type Enumeration struct {
+	// Counts holds the number of states at each distance from the start.
+	Counts []int64
+	// Antipodes holds up to EnumerateOptions.Antipodes of the states furthest
+	// from the start, in no particular order.
+	Antipodes []Puzzle
+}
+
+// MaxDistance returns the greatest distance of any reachable state from the
+// start. When enumerating from the goal, this is the longest optimal solution
+// of any puzzle of that size, sometimes called God's number.
+func (e Enumeration) MaxDistance() int {
+	return len(e.Counts) - 1
+}
+
+// Total returns the number of reachable states.
+func (e Enumeration) Total() int64 {
+	var total int64
+	for _, c := range e.Counts {
+		total += c
+	}
+	return total
+}
+
+// maxEnumerateCells is the largest board EnumerateStates supports, so that
+// every tile value fits in a byte with one value left over for
+// indistinguishable tiles.
+const maxEnumerateCells = 255
+
+// otherTile marks tiles that are not told apart in an enumeration.
+const otherTile = 0xff
+
+// EnumerateStates counts the states reachable from start by breadth-first
+// search, layer by layer.
+//
+// Only the previous, current and next layers are needed at any time: since
+// every move can be undone, the neighbors of a state at distance d are at
+// distance d-1, d or d+1. Each new layer is generated as sorted runs of
+// states, which are merged to drop duplicates and states already in the two
+// layers before it. Unless opts.InMemory is set, layers and runs are kept in
+// temporary files, so the enumeration is limited by disk space rather than
+// memory.
+func EnumerateStates(start Puzzle, opts EnumerateOptions) (Enumeration, error) {
+	if err := start.requireSingleEmpty(); err != nil {
+		return Enumeration{}, err
+	}
+	if err := start.requireDistinct(); err != nil {
+		return Enumeration{}, err
+	}
+	if err := start.requireNoBlocked(); err != nil {
+		return Enumeration{}, err
+	}
+	rows, cols := len(start.grid), len(start.grid[0])
+	if rows*cols > maxEnumerateCells {
+		return Enumeration{}, &InvalidPuzzleError{fmt.Sprintf(
+			"boards with more than %d cells cannot be enumerated; got %dx%d", maxEnumerateCells, rows, cols,
+		)}
+	}
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = 1 << 20
+	}
+
+	keep := make([]bool, rows*cols)
+	if len(opts.Tiles) == 0 {
+		for i := range keep {
+			keep[i] = true
+		}
+	}
+	for _, t := range opts.Tiles {
+		if t < 0 || t >= rows*cols {
+			return Enumeration{}, &InvalidPuzzleError{fmt.Sprintf("tile %d is not on a %dx%d board", t, rows, cols)}
+		}
+		keep[t] = true
+	}
+	keep[start.emptyTile.value] = true
+
+	root := make([]byte, rows*cols)
+	for i, val := range start.Values() {
+		root[i] = otherTile
+		if keep[val] {
+			root[i] = byte(val)
+		}
+	}
+
+	sp, err := newSpool(opts)
+	if err != nil {
+		return Enumeration{}, err
+	}
+	defer sp.close()
+
+	e := &enumerator{
+		rows:       rows,
+		cols:       cols,
+		emptyValue: byte(start.emptyTile.value),
+		spool:      sp,
+		chunkSize:  opts.ChunkSize,
+	}
+	if len(opts.Tiles) == 0 {
+		e.antipodes = opts.Antipodes
+	}
+	return e.run(root)
+}
+
+type enumerator struct {
+	rows, cols int
+	emptyValue byte
+	spool      *spool
+	chunkSize  int
+	antipodes  int
+}
+
+func (e *enumerator) run(root []byte) (Enumeration, error) {
+	size := len(root)
+	empty := e.spool.create()
+	if err := empty.finish(); err != nil {
+		return Enumeration{}, err
+	}
+	current := e.spool.create()
+	if _, err := current.w.Write(root); err != nil {
+		return Enumeration{}, err
+	}
+	if err := current.finish(); err != nil {
+		return Enumeration{}, err
+	}
+
+	result := Enumeration{Counts: []int64{1}}
+	previous := empty
+	for {
+		runs, err := e.expand(current, size)
+		if err != nil {
+			return Enumeration{}, err
+		}
+		next, count, err := e.merge(runs, previous, current, size)
+		for _, r := range runs {
+			r.remove()
+		}
+		if err != nil {
+			return Enumeration{}, err
+		}
+		previous.remove()
+
+		if count == 0 {
+			next.remove()
+			result.Antipodes, err = e.sample(current, size)
+			current.remove()
+			return result, err
+		}
+		result.Counts = append(result.Counts, count)
+		previous, current = current, next
+	}
+}
+
+// sample returns up to e.antipodes states from layer as puzzles.
+func (e *enumerator) sample(layer *spoolFile, size int) ([]Puzzle, error) {
+	if e.antipodes <= 0 {
+		return nil, nil
+	}
+	in, err := layer.open(size)
+	if err != nil {
+		return nil, err
+	}
+	defer in.close()
+
+	var puzzles []Puzzle
+	for len(puzzles) < e.antipodes && in.next() {
+		grid := make([][]int, e.rows)
+		for row := range grid {
+			grid[row] = make([]int, e.cols)
+			for col := range grid[row] {
+				grid[row][col] = int(in.record[row*e.cols+col])
+			}
+		}
+		p, err := NewPuzzle(grid, int(e.emptyValue))
+		if err != nil {
+			return nil, err
+		}
+		puzzles = append(puzzles, *p)
+	}
+	return puzzles, in.err
+}
+
+// expand writes the neighbors of every state in layer as sorted runs without
+// duplicates.
+func (e *enumerator) expand(layer *spoolFile, size int) ([]*spoolFile, error) {
+	in, err := layer.open(size)
+	if err != nil {
+		return nil, err
+	}
+	defer in.close()
+
+	var runs []*spoolFile
+	chunk := make([]byte, 0, e.chunkSize*size)
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		run := e.spool.create()
+		records := sortRecords(chunk, size)
+		for i := 0; i < len(records); i += size {
+			if i > 0 && bytes.Equal(records[i-size:i], records[i:i+size]) {
+				continue
+			}
+			if _, err := run.w.Write(records[i : i+size]); err != nil {
+				return err
+			}
+		}
+		runs = append(runs, run)
+		chunk = chunk[:0]
+		return run.finish()
+	}
+
+	for in.next() {
+		state := in.record
+		empty := bytes.IndexByte(state, e.emptyValue)
+		row, col := empty/e.cols, empty%e.cols
+		for _, n := range []struct {
+			ok  bool
+			src int
+		}{
+			{row > 0, empty - e.cols},
+			{row < e.rows-1, empty + e.cols},
+			{col > 0, empty - 1},
+			{col < e.cols-1, empty + 1},
+		} {
+			if !n.ok {
+				continue
+			}
+			start := len(chunk)
+			chunk = append(chunk, state...)
+			chunk[start+empty], chunk[start+n.src] = chunk[start+n.src], chunk[start+empty]
+		}
+		if len(chunk) >= e.chunkSize*size {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := in.err; err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// merge combines sorted runs into the next layer, dropping duplicates and any
+// state found in the previous or current layers. It returns the new layer and
+// the number of states in it.
+func (e *enumerator) merge(runs []*spoolFile, previous, current *spoolFile, size int) (*spoolFile, int64, error) {
+	next := e.spool.create()
+
+	var readers recordHeap
+	var all []*recordReader
+	defer func() {
+		for _, r := range all {
+			r.close()
+		}
+	}()
+	open := func(f *spoolFile) (*recordReader, error) {
+		r, err := f.open(size)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, r)
+		return r, nil
+	}
+
+	for _, run := range runs {
+		r, err := open(run)
+		if err != nil {
+			return nil, 0, err
+		}
+		if r.next() {
+			readers = append(readers, r)
+		} else if r.err != nil {
+			return nil, 0, r.err
+		}
+	}
+	heap.Init(&readers)
+
+	seen := make([]*recordReader, 0, 2)
+	for _, f := range []*spoolFile{previous, current} {
+		r, err := open(f)
+		if err != nil {
+			return nil, 0, err
+		}
+		r.next()
+		seen = append(seen, r)
+	}
+
+	var count int64
+	last := make([]byte, 0, size)
+	haveLast := false
+	for readers.Len() > 0 {
+		r := readers[0]
+		if !haveLast || !bytes.Equal(r.record, last) {
+			if !containsRecord(seen, r.record) {
+				if _, err := next.w.Write(r.record); err != nil {
+					return nil, 0, err
+				}
+				count++
+			}
+			last = append(last[:0], r.record...)
+			haveLast = true
+		}
+
+		if r.next() {
+			heap.Fix(&readers, 0)
+		} else {
+			if r.err != nil {
+				return nil, 0, r.err
+			}
+			heap.Pop(&readers)
+		}
+	}
+	for _, r := range seen {
+		if r.err != nil {
+			return nil, 0, r.err
+		}
+	}
+	return next, count, next.finish()
+}
+
+// containsRecord advances each of the sorted readers past any record smaller
+// than state, and reports whether any of them holds state.
+func containsRecord(readers []*recordReader, state []byte) bool {
+	found := false
+	for _, r := range readers {
+		for r.ok && bytes.Compare(r.record, state) < 0 {
+			r.next()
+		}
+		if r.ok && bytes.Equal(r.record, state) {
+			found = true
+		}
+	}
+	return found
+}
+
+// sortRecords sorts the fixed-size records in data in place and returns it.
+func sortRecords(data []byte, size int) []byte {
+	sort.Sort(recordSlice{data: data, size: size, tmp: make([]byte, size)})
+	return data
+}
+
+type recordSlice struct {
+	data []byte
+	size int
+	tmp  []byte
+}
+
+func (s recordSlice) Len() int { return len(s.data) / s.size }
+
+func (s recordSlice) Less(i, j int) bool {
+	return bytes.Compare(s.data[i*s.size:(i+1)*s.size], s.data[j*s.size:(j+1)*s.size]) < 0
+}
+
+func (s recordSlice) Swap(i, j int) {
+	a, b := s.data[i*s.size:(i+1)*s.size], s.data[j*s.size:(j+1)*s.size]
+	copy(s.tmp, a)
+	copy(a, b)
+	copy(b, s.tmp)
+}
+
+// recordHeap orders readers by their current record.
+type recordHeap []*recordReader
+
+func (h recordHeap) Len() int           { return len(h) }
+func (h recordHeap) Less(i, j int) bool { return bytes.Compare(h[i].record, h[j].record) < 0 }
+func (h recordHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *recordHeap) Push(x any)        { *h = append(*h, x.(*recordReader)) }
+
+func (h *recordHeap) Pop() any {
+	old := *h
+	r := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return r
+}
+
+// spool creates sequences of records, either in memory or in temporary files.
+type spool struct {
+	dir      string
+	inMemory bool
+	created  int
+}
+
+func newSpool(opts EnumerateOptions) (*spool, error) {
+	if opts.InMemory {
+		return &spool{inMemory: true}, nil
+	}
+	dir, err := os.MkdirTemp(opts.TempDir, "slide-puzzle-enumerate-")
+	if err != nil {
+		return nil, err
+	}
+	return &spool{dir: dir}, nil
+}
+
+// close removes any files left behind.
+func (s *spool) close() {
+	if !s.inMemory {
+		os.RemoveAll(s.dir)
+	}
+}
+
+// spoolFile is a sequence of records being written or already written. If
+// creating it failed, the error is reported by finish.
+type spoolFile struct {
+	w    *bufio.Writer
+	buf  *bytes.Buffer
+	file *os.File
+	path string
+	err  error
+}
+
+func (s *spool) create() *spoolFile {
+	if s.inMemory {
+		buf := &bytes.Buffer{}
+		return &spoolFile{buf: buf, w: bufio.NewWriter(buf)}
+	}
+	s.created++
+	path := filepath.Join(s.dir, fmt.Sprintf("%06d", s.created))
+	file, err := os.Create(path)
+	if err != nil {
+		return &spoolFile{w: bufio.NewWriter(io.Discard), err: err}
+	}
+	return &spoolFile{file: file, path: path, w: bufio.NewWriter(file)}
+}
+
+// finish flushes and closes the file for writing.
+func (f *spoolFile) finish() error {
+	if f.err != nil {
+		return f.err
+	}
+	if err := f.w.Flush(); err != nil {
+		return err
+	}
+	if f.file != nil {
+		return f.file.Close()
+	}
+	return nil
+}
+
+func (f *spoolFile) open(size int) (*recordReader, error) {
+	r := &recordReader{record: make([]byte, size)}
+	if f.buf != nil {
+		r.r = bufio.NewReader(bytes.NewReader(f.buf.Bytes()))
+		return r, nil
+	}
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, err
+	}
+	r.r = bufio.NewReader(file)
+	r.closer = file
+	return r, nil
+}
+
+func (f *spoolFile) remove() {
+	if f.buf != nil {
+		f.buf = nil
+		return
+	}
+	os.Remove(f.path)
+}
+
+// recordReader reads fixed-size records one at a time.
+type recordReader struct {
+	r      *bufio.Reader
+	closer io.Closer
+	record []byte
+	// ok reports whether record holds a valid record.
+	ok  bool
+	err error
+}
+
+// next reads the next record, returning false at the end of the input or on
+// error.
+func (r *recordReader) next() bool {
+	_, err := io.ReadFull(r.r, r.record)
+	if err != nil {
+		if err != io.EOF {
+			r.err = err
+		}
+		r.ok = false
+		return false
+	}
+	r.ok = true
+	return true
+}
+
+func (r *recordReader) close() {
+	if r.closer != nil {
+		r.closer.Close()
+	}
+}
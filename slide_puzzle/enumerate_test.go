@@ -0,0 +1,155 @@
+package slide_puzzle
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// bfsLayerCounts counts states by distance with a plain in-memory BFS over
+// abstract states, as a reference for EnumerateStates.
+func bfsLayerCounts(t *testing.T, start Puzzle, tiles []int) []int64 {
+	t.Helper()
+	abstract := func(p Puzzle) string {
+		keep := map[int]bool{p.emptyTile.value: true}
+		for _, tile := range tiles {
+			keep[tile] = true
+		}
+		b := []byte{}
+		for _, v := range p.Values() {
+			if len(tiles) > 0 && !keep[v] {
+				v = otherTile
+			}
+			b = append(b, byte(v))
+		}
+		return string(b)
+	}
+
+	visited := map[string]bool{abstract(start): true}
+	layer := []Puzzle{start}
+	var counts []int64
+	for len(layer) > 0 {
+		counts = append(counts, int64(len(layer)))
+		var next []Puzzle
+		for _, p := range layer {
+			for _, m := range allMoves {
+				if !p.getMoves()[m] {
+					continue
+				}
+				child, err := p.makeMove(m)
+				if err != nil {
+					t.Fatalf("makeMove() error: %v", err)
+				}
+				if key := abstract(child); !visited[key] {
+					visited[key] = true
+					next = append(next, child)
+				}
+			}
+		}
+		layer = next
+	}
+	return counts
+}
+
+func TestEnumerateStates(t *testing.T) {
+	tests := []struct {
+		name       string
+		rows, cols int
+		empty      int
+		tiles      []int
+	}{
+		{name: "2x3", rows: 2, cols: 3, empty: 0},
+		{name: "3x2 empty in middle", rows: 3, cols: 2, empty: 3},
+		{name: "1x4", rows: 1, cols: 4, empty: 2},
+		{name: "2x4 subset", rows: 2, cols: 4, empty: 0, tiles: []int{1, 2, 5}},
+	}
+
+	for _, tt := range tests {
+		start, err := NewPuzzle(goalGrid(tt.rows, tt.cols), tt.empty)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		want := bfsLayerCounts(t, *start, tt.tiles)
+
+		for _, opts := range []EnumerateOptions{
+			{Tiles: tt.tiles, InMemory: true},
+			{Tiles: tt.tiles, InMemory: true, ChunkSize: 7},
+			{Tiles: tt.tiles, TempDir: t.TempDir(), ChunkSize: 5},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := EnumerateStates(*start, opts)
+				if err != nil {
+					t.Fatalf("EnumerateStates() error: %v", err)
+				}
+				if diff := cmp.Diff(want, got.Counts); diff != "" {
+					t.Errorf("EnumerateStates(%+v) counts mismatch (-want +got):\n%s", opts, diff)
+				}
+			})
+		}
+	}
+
+	t.Run("3x3 matches known counts", func(t *testing.T) {
+		start, err := NewPuzzle(goalGrid(3, 3), 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		got, err := EnumerateStates(*start, EnumerateOptions{TempDir: t.TempDir(), ChunkSize: 50000})
+		if err != nil {
+			t.Fatalf("EnumerateStates() error: %v", err)
+		}
+		want := []int64{
+			1, 2, 4, 8, 16, 20, 39, 62, 116, 152, 286, 396, 748, 1024, 1893, 2512,
+			4485, 5638, 9529, 10878, 16993, 17110, 23952, 20224, 24047, 15578,
+			14560, 6274, 3910, 760, 221, 2,
+		}
+		if diff := cmp.Diff(want, got.Counts); diff != "" {
+			t.Errorf("EnumerateStates() counts mismatch (-want +got):\n%s", diff)
+		}
+		if got.Total() != 181440 {
+			t.Errorf("Total() = %d, want 181440", got.Total())
+		}
+	})
+
+	t.Run("antipodes are furthest from the goal", func(t *testing.T) {
+		start, err := NewPuzzle(goalGrid(2, 3), 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		got, err := EnumerateStates(*start, EnumerateOptions{InMemory: true, Antipodes: 10})
+		if err != nil {
+			t.Fatalf("EnumerateStates() error: %v", err)
+		}
+		if got.MaxDistance() != 21 {
+			t.Errorf("MaxDistance() = %d, want 21", got.MaxDistance())
+		}
+		if len(got.Antipodes) != 1 {
+			t.Fatalf("EnumerateStates() returned %d antipodes, want 1", len(got.Antipodes))
+		}
+		moves, err := got.Antipodes[0].Solve()
+		if err != nil {
+			t.Fatalf("Solve() error: %v", err)
+		}
+		if len(moves) != 21 {
+			t.Errorf("antipode %v solved in %d moves, want 21", got.Antipodes[0], len(moves))
+		}
+	})
+
+	t.Run("temporary files are removed", func(t *testing.T) {
+		dir := t.TempDir()
+		start, err := NewPuzzle(goalGrid(2, 3), 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		if _, err := EnumerateStates(*start, EnumerateOptions{TempDir: dir, ChunkSize: 3}); err != nil {
+			t.Fatalf("EnumerateStates() error: %v", err)
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("ReadDir() error: %v", err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("EnumerateStates() left %d entries in %s", len(entries), dir)
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package slide_puzzle
+
+// flatPuzzle is a mutable, row-major representation of a puzzle for search
+// algorithms that make and undo millions of moves. Unlike Puzzle.makeMove,
+// moves are applied in place and the Manhattan distance is kept up to date
+// incrementally.
+type flatPuzzle struct {
+	rows, cols int
+	cells      []int
+	// empty is the index of the empty tile in cells.
+	empty      int
+	emptyValue int
+	// h is the Manhattan distance of the current state.
+	h int
+}
+
+func newFlatPuzzle(p Puzzle) *flatPuzzle {
+	f := &flatPuzzle{
+		rows:       len(p.grid),
+		cols:       len(p.grid[0]),
+		cells:      p.Values(),
+		empty:      p.emptyTile.coord.row*len(p.grid[0]) + p.emptyTile.coord.col,
+		emptyValue: p.emptyTile.value,
+	}
+	f.h = manhattanDistance(p)
+	return f
+}
+
+func (f *flatPuzzle) clone() *flatPuzzle {
+	c := *f
+	c.cells = append([]int(nil), f.cells...)
+	return &c
+}
+
+// distance returns the Manhattan distance from index i to the goal position of
+// value val.
+func (f *flatPuzzle) distance(val, i int) int {
+	return abs(i/f.cols-val/f.cols) + abs(i%f.cols-val%f.cols)
+}
+
+// source returns the index of the tile that move m slides into the empty
+// space, or -1 if the move is not possible.
+func (f *flatPuzzle) source(m Move) int {
+	row, col := f.empty/f.cols, f.empty%f.cols
+	src := -1
+	switch m {
+	case North:
+		if row < f.rows-1 {
+			src = f.empty + f.cols
+		}
+	case South:
+		if row > 0 {
+			src = f.empty - f.cols
+		}
+	case East:
+		if col > 0 {
+			src = f.empty - 1
+		}
+	case West:
+		if col < f.cols-1 {
+			src = f.empty + 1
+		}
+	}
+	if src >= 0 && f.cells[src] == Blocked {
+		return -1
+	}
+	return src
+}
+
+// apply slides the tile at index src, which must be adjacent to the empty
+// space, into the empty space.
+func (f *flatPuzzle) apply(src int) {
+	val := f.cells[src]
+	f.h += f.distance(val, f.empty) - f.distance(val, src)
+	f.cells[f.empty] = val
+	f.cells[src] = f.emptyValue
+	f.empty = src
+}
+
+// key returns a compact string identifying the arrangement of tiles, for use
+// in duplicate detection.
+func (f *flatPuzzle) key() string {
+	if len(f.cells) <= 1<<8 {
+		b := make([]byte, len(f.cells))
+		for i, val := range f.cells {
+			b[i] = byte(val)
+		}
+		return string(b)
+	}
+	b := make([]byte, 0, 2*len(f.cells))
+	for _, val := range f.cells {
+		b = append(b, byte(val>>8), byte(val))
+	}
+	return string(b)
+}
+
+// puzzle converts f back into a Puzzle.
+func (f *flatPuzzle) puzzle() Puzzle {
+	grid := make([][]int, f.rows)
+	for row := range grid {
+		grid[row] = append([]int(nil), f.cells[row*f.cols:(row+1)*f.cols]...)
+	}
+	return Puzzle{
+		grid: grid,
+		emptyTile: tile{
+			value: f.emptyValue,
+			coord: coord{row: f.empty / f.cols, col: f.empty % f.cols},
+		},
+	}
+}
@@ -0,0 +1,64 @@
+package slide_puzzle
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestFlatPuzzle(t *testing.T) {
+	puzzle, err := Scramble(3, 4, 5, 50, rand.New(rand.NewPCG(1, 2)))
+	if err != nil {
+		t.Fatalf("Scramble() error: %v", err)
+	}
+
+	flat := newFlatPuzzle(*puzzle)
+	want := *puzzle
+	rng := rand.New(rand.NewPCG(3, 4))
+	for range 100 {
+		m := allMoves[rng.IntN(len(allMoves))]
+		src := flat.source(m)
+		if !want.getMoves()[m] {
+			if src >= 0 {
+				t.Fatalf("source(%v) = %d, want -1 for invalid move", m, src)
+			}
+			continue
+		}
+
+		flat.apply(src)
+		want, err = want.makeMove(m)
+		if err != nil {
+			t.Fatalf("makeMove(%v) error: %v", m, err)
+		}
+
+		got := flat.puzzle()
+		assertPuzzlesEqual(t, &want, &got)
+		if flat.h != manhattanDistance(want) {
+			t.Fatalf("incremental h = %d, want %d", flat.h, manhattanDistance(want))
+		}
+	}
+}
+
+func TestFlatPuzzleKey(t *testing.T) {
+	for _, size := range []struct{ rows, cols int }{{3, 3}, {20, 20}} {
+		a, err := Scramble(size.rows, size.cols, 0, 10, rand.New(rand.NewPCG(1, 2)))
+		if err != nil {
+			t.Fatalf("Scramble() error: %v", err)
+		}
+		flat := newFlatPuzzle(*a)
+		before := flat.key()
+
+		src := flat.source(North)
+		if src < 0 {
+			src = flat.source(South)
+		}
+		empty := flat.empty
+		flat.apply(src)
+		if flat.key() == before {
+			t.Errorf("%dx%d: key did not change after a move", size.rows, size.cols)
+		}
+		flat.apply(empty)
+		if flat.key() != before {
+			t.Errorf("%dx%d: key changed after undoing a move", size.rows, size.cols)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package slide_puzzle
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// goalGrid returns the solved grid for a puzzle with the given dimensions.
+func goalGrid(rows, cols int) [][]int {
+	grid := make([][]int, rows)
+	for row := range grid {
+		grid[row] = make([]int, cols)
+		for col := range grid[row] {
+			grid[row][col] = row*cols + col
+		}
+	}
+	return grid
+}
+
+// Goal returns the solved puzzle with the given dimensions and empty tile.
+func Goal(rows, cols, emptyTileValue int) (*Puzzle, error) {
+	if rows <= 0 || cols <= 0 {
+		return nil, &InvalidPuzzleError{fmt.Sprintf("puzzle dimensions must be positive; got %dx%d", rows, cols)}
+	}
+	return NewPuzzle(goalGrid(rows, cols), emptyTileValue)
+}
+
+// Scramble returns a puzzle created by making the given number of random moves
+// from the goal state. A move never immediately undoes the one before it.
+// Since every move is reversible, the result is always solvable, though its
+// optimal solution may be shorter than the number of moves made.
+func Scramble(rows, cols, emptyTileValue, moves int, rng *rand.Rand) (*Puzzle, error) {
+	puzzle, err := Goal(rows, cols, emptyTileValue)
+	if err != nil {
+		return nil, err
+	}
+
+	current := *puzzle
+	var last Move
+	for i := range moves {
+		valid := current.getMoves()
+		candidates := make([]Move, 0, len(allMoves))
+		for _, m := range allMoves {
+			if valid[m] && (i == 0 || m != last.opposite()) {
+				candidates = append(candidates, m)
+			}
+		}
+		if len(candidates) == 0 {
+			if len(valid) == 0 {
+				// A 1x1 board has no moves at all.
+				break
+			}
+			// Only possible at the ends of 1xN and Nx1 boards: turn around.
+			candidates = append(candidates, last.opposite())
+		}
+
+		last = candidates[rng.IntN(len(candidates))]
+		current, err = current.makeMove(last)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &current, nil
+}
+
+// maxLayerCells is the largest board ScrambleDistance searches breadth-first.
+// A 2x5 board has 10! permutations, each taking a byte in the set of states
+// visited.
+const maxLayerCells = 10
+
+// scrambleDistanceTimeout is how long ScrambleDistance spends solving
+// scrambles of larger boards before giving up.
+var scrambleDistanceTimeout = time.Minute
+
+// ScrambleDistance returns a random puzzle whose optimal solution takes
+// exactly distance moves, unlike Scramble, whose puzzles are often much closer
+// to the goal than the number of moves made.
+//
+// Boards with at most 10 cells are searched breadth-first backwards from the
+// goal, one layer of states at each distance, and the puzzle is picked
+// uniformly from the layer at the given distance. Larger boards are scrambled
+// by random moves and solved with SolveIDAStar until the optimal solution
+// takes at least distance moves; the state that many moves from the goal
+// along that solution is then exactly that far from it. This takes as long as
+// solving a puzzle of that distance optimally, which grows exponentially with
+// the distance: on a 4x4 board, 45 moves take about a second and 55 moves
+// half a minute. After a minute, ScrambleDistance gives up with an error.
+func ScrambleDistance(rows, cols, emptyTileValue, distance int, rng *rand.Rand) (*Puzzle, error) {
+	goal, err := Goal(rows, cols, emptyTileValue)
+	if err != nil {
+		return nil, err
+	}
+	if distance < 0 {
+		return nil, &InvalidPuzzleError{fmt.Sprintf("distance must not be negative; got %d", distance)}
+	}
+	if rows*cols <= maxLayerCells {
+		return scrambleLayer(*goal, distance, rng)
+	}
+
+	var canceled atomic.Bool
+	timer := time.AfterFunc(scrambleDistanceTimeout, func() { canceled.Store(true) })
+	defer timer.Stop()
+
+	const attempts = 100
+	moves := 2 * distance
+	for range attempts {
+		p, err := Scramble(rows, cols, emptyTileValue, moves, rng)
+		if err != nil {
+			return nil, err
+		}
+		solution, err := p.solveIDAStar(0, nil, &canceled)
+		if errors.Is(err, errSearchCanceled) {
+			return nil, fmt.Errorf(
+				"gave up generating a puzzle %d moves from the goal on a %dx%d board after %s; try a shorter distance",
+				distance, rows, cols, scrambleDistanceTimeout,
+			)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(solution) < distance {
+			moves = min(moves+max(1, moves/4), 10*distance)
+			continue
+		}
+		// Every state along an optimal solution is solved optimally by the
+		// rest of it.
+		current := *p
+		for _, m := range solution[:len(solution)-distance] {
+			if current, err = current.makeMove(m); err != nil {
+				return nil, err
+			}
+		}
+		return &current, nil
+	}
+	return nil, fmt.Errorf("could not generate a puzzle %d moves from the goal on a %dx%d board in %d attempts", distance, rows, cols, attempts)
+}
+
+// scrambleLayer returns a random state at the given distance from goal, found
+// by breadth-first search from goal one layer at a time. States are
+// identified by the rank of their permutation of tile values.
+func scrambleLayer(goal Puzzle, distance int, rng *rand.Rand) (*Puzzle, error) {
+	board := newFlatPuzzle(goal)
+	visited := make([]bool, factorial(len(board.cells)))
+	root := rankPermutation(board.cells)
+	visited[root] = true
+	layer := []uint32{uint32(root)}
+	for d := 0; d < distance; d++ {
+		var next []uint32
+		for _, rank := range layer {
+			unrankPermutation(int(rank), board.cells)
+			board.empty = slices.Index(board.cells, board.emptyValue)
+			for _, m := range allMoves {
+				src := board.source(m)
+				if src < 0 {
+					continue
+				}
+				empty := board.empty
+				board.apply(src)
+				if r := rankPermutation(board.cells); !visited[r] {
+					visited[r] = true
+					next = append(next, uint32(r))
+				}
+				board.apply(empty)
+			}
+		}
+		if len(next) == 0 {
+			return nil, &InvalidPuzzleError{fmt.Sprintf(
+				"no %dx%d puzzle is %d moves from the goal; the most any needs is %d",
+				board.rows, board.cols, distance, d,
+			)}
+		}
+		layer = next
+	}
+
+	unrankPermutation(int(layer[rng.IntN(len(layer))]), board.cells)
+	board.empty = slices.Index(board.cells, board.emptyValue)
+	p := board.puzzle()
+	return &p, nil
+}
@@ -0,0 +1,123 @@
+package slide_puzzle
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func TestScramble(t *testing.T) {
+	t.Run("result is a valid solvable puzzle", func(t *testing.T) {
+		puzzle, err := Scramble(2, 3, 0, 20, rand.New(rand.NewPCG(1, 2)))
+		if err != nil {
+			t.Fatalf("Scramble() error: %v", err)
+		}
+
+		// Round-trip through NewPuzzle to validate the grid.
+		if _, err := NewPuzzle(puzzle.grid, 0); err != nil {
+			t.Fatalf("Scramble() produced an invalid grid %v: %v", puzzle.grid, err)
+		}
+		if _, err := puzzle.Solve(); err != nil {
+			t.Fatalf("Solve() error on scrambled puzzle: %v", err)
+		}
+	})
+
+	t.Run("same seed gives same puzzle", func(t *testing.T) {
+		a, err := Scramble(3, 3, 0, 50, rand.New(rand.NewPCG(7, 7)))
+		if err != nil {
+			t.Fatalf("Scramble() error: %v", err)
+		}
+		b, err := Scramble(3, 3, 0, 50, rand.New(rand.NewPCG(7, 7)))
+		if err != nil {
+			t.Fatalf("Scramble() error: %v", err)
+		}
+		assertPuzzlesEqual(t, a, b)
+	})
+
+	t.Run("zero moves gives goal", func(t *testing.T) {
+		puzzle, err := Scramble(3, 2, 5, 0, rand.New(rand.NewPCG(1, 1)))
+		if err != nil {
+			t.Fatalf("Scramble() error: %v", err)
+		}
+		if !puzzle.isSolved() {
+			t.Errorf("Scramble() with zero moves = %v, want solved puzzle", puzzle)
+		}
+	})
+
+	t.Run("single row", func(t *testing.T) {
+		if _, err := Scramble(1, 4, 0, 10, rand.New(rand.NewPCG(1, 1))); err != nil {
+			t.Fatalf("Scramble() error: %v", err)
+		}
+	})
+
+	t.Run("invalid dimensions return error", func(t *testing.T) {
+		_, err := Scramble(0, 3, 0, 10, rand.New(rand.NewPCG(1, 1)))
+		var invalidErr *InvalidPuzzleError
+		if !errors.As(err, &invalidErr) {
+			t.Fatalf("Scramble() error type = %T, want *InvalidPuzzleError", err)
+		}
+	})
+}
+
+func TestScrambleDistance(t *testing.T) {
+	tests := []struct {
+		name       string
+		rows, cols int
+		empty      int
+		distance   int
+	}{
+		{name: "goal", rows: 3, cols: 3, empty: 0, distance: 0},
+		{name: "small board", rows: 3, cols: 3, empty: 8, distance: 24},
+		{name: "farthest on small board", rows: 2, cols: 3, empty: 0, distance: 21},
+		{name: "large board", rows: 3, cols: 4, empty: 0, distance: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			puzzle, err := ScrambleDistance(tt.rows, tt.cols, tt.empty, tt.distance, rand.New(rand.NewPCG(3, 4)))
+			if err != nil {
+				t.Fatalf("ScrambleDistance() error: %v", err)
+			}
+			moves, err := puzzle.SolveIDAStar(1)
+			if err != nil {
+				t.Fatalf("SolveIDAStar() error: %v", err)
+			}
+			if len(moves) != tt.distance {
+				t.Errorf("ScrambleDistance(%d) = %v, which takes %d moves", tt.distance, puzzle, len(moves))
+			}
+		})
+	}
+
+	for name, distance := range map[string]int{
+		"beyond the farthest state": 22,
+		"negative distance":         -1,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ScrambleDistance(2, 3, 0, distance, rand.New(rand.NewPCG(1, 1)))
+			var invalidErr *InvalidPuzzleError
+			if !errors.As(err, &invalidErr) {
+				t.Errorf("ScrambleDistance(%d) error type = %T, want *InvalidPuzzleError", distance, err)
+			}
+		})
+	}
+
+	t.Run("gives up on long distances", func(t *testing.T) {
+		defer func(timeout time.Duration) { scrambleDistanceTimeout = timeout }(scrambleDistanceTimeout)
+		scrambleDistanceTimeout = 100 * time.Millisecond
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := ScrambleDistance(4, 4, 0, 70, rand.New(rand.NewPCG(1, 1)))
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			if err == nil {
+				t.Errorf("ScrambleDistance(70) error = nil, want an error after the timeout")
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("ScrambleDistance(70) did not give up")
+		}
+	})
+}
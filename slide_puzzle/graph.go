@@ -0,0 +1,413 @@
+package slide_puzzle
+
+import (
+	"container/heap"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Board is the shape of a sliding puzzle given as a graph: cells are numbered
+// from 0, and a tile can slide between any two adjacent cells. It covers
+// boards that Puzzle cannot describe, such as ones that wrap around or have
+// hexagonal or triangular cells.
+type Board struct {
+	// neighbors lists the cells adjacent to each cell, in ascending order.
+	neighbors [][]int
+}
+
+// NewBoard returns a board with the given adjacency lists: neighbors[i] holds
+// the cells adjacent to cell i. Adjacency must be symmetric, and a cell cannot
+// be adjacent to itself.
+func NewBoard(neighbors [][]int) (*Board, error) {
+	if len(neighbors) < 2 {
+		return nil, &InvalidPuzzleError{fmt.Sprintf("board must have at least 2 cells; got %d", len(neighbors))}
+	}
+	b := &Board{neighbors: make([][]int, len(neighbors))}
+	for cell, adjacent := range neighbors {
+		for _, n := range adjacent {
+			switch {
+			case n < 0 || n >= len(neighbors):
+				return nil, &InvalidPuzzleError{fmt.Sprintf("cell %d has neighbor %d out of range [0, %d)", cell, n, len(neighbors))}
+			case n == cell:
+				return nil, &InvalidPuzzleError{fmt.Sprintf("cell %d cannot be its own neighbor", cell)}
+			case !slices.Contains(neighbors[n], cell):
+				return nil, &InvalidPuzzleError{fmt.Sprintf("cell %d is a neighbor of %d, but not the other way around", n, cell)}
+			}
+		}
+		b.neighbors[cell] = slices.Compact(slices.Sorted(slices.Values(adjacent)))
+	}
+	return b, nil
+}
+
+// GridBoard returns the board of an ordinary rows x cols puzzle, with cells
+// numbered in row-major order.
+func GridBoard(rows, cols int) (*Board, error) {
+	return gridLikeBoard(rows, cols, func(row, col int) [][2]int {
+		return [][2]int{{row - 1, col}, {row + 1, col}, {row, col - 1}, {row, col + 1}}
+	})
+}
+
+// TorusBoard returns a rows x cols board whose edges wrap around: the first
+// and last cells of each row are adjacent, as are the first and last cells of
+// each column.
+func TorusBoard(rows, cols int) (*Board, error) {
+	return gridLikeBoard(rows, cols, func(row, col int) [][2]int {
+		return [][2]int{
+			{(row + rows - 1) % rows, col}, {(row + 1) % rows, col},
+			{row, (col + cols - 1) % cols}, {row, (col + 1) % cols},
+		}
+	})
+}
+
+// HexBoard returns a board of rows x cols hexagonal cells, numbered in
+// row-major order, where odd rows are shifted half a cell to the right. Each
+// cell has up to six neighbors: two in its own row, and two in each of the
+// rows above and below.
+func HexBoard(rows, cols int) (*Board, error) {
+	return gridLikeBoard(rows, cols, func(row, col int) [][2]int {
+		// The cells above and below start half a cell to the left on even
+		// rows, and half a cell to the right on odd rows.
+		shift := row%2 - 1
+		return [][2]int{
+			{row, col - 1}, {row, col + 1},
+			{row - 1, col + shift}, {row - 1, col + shift + 1},
+			{row + 1, col + shift}, {row + 1, col + shift + 1},
+		}
+	})
+}
+
+// gridLikeBoard builds a board whose cells are laid out in rows x cols, where
+// adjacent returns candidate neighbors of each cell. Candidates off the board
+// are dropped.
+func gridLikeBoard(rows, cols int, adjacent func(row, col int) [][2]int) (*Board, error) {
+	if rows <= 0 || cols <= 0 {
+		return nil, &InvalidPuzzleError{fmt.Sprintf("board dimensions must be positive; got %dx%d", rows, cols)}
+	}
+	neighbors := make([][]int, rows*cols)
+	for row := range rows {
+		for col := range cols {
+			cell := row*cols + col
+			for _, n := range adjacent(row, col) {
+				// Small tori can list a cell twice or as its own neighbor.
+				if n[0] < 0 || n[0] >= rows || n[1] < 0 || n[1] >= cols {
+					continue
+				}
+				if other := n[0]*cols + n[1]; other != cell && !slices.Contains(neighbors[cell], other) {
+					neighbors[cell] = append(neighbors[cell], other)
+				}
+			}
+		}
+	}
+	return NewBoard(neighbors)
+}
+
+// TriangleBoard returns a triangle divided into rows*rows triangular cells.
+// Row r, counting from the apex, has 2r+1 cells that alternately point up and
+// down, starting with one pointing up. Cells are numbered row by row from the
+// apex. Each cell is adjacent to the cells beside it in its row, and to the
+// cell below it if it points up, or above it if it points down.
+func TriangleBoard(rows int) (*Board, error) {
+	if rows <= 0 {
+		return nil, &InvalidPuzzleError{fmt.Sprintf("board must have a positive number of rows; got %d", rows)}
+	}
+	neighbors := make([][]int, rows*rows)
+	// Row r starts at cell r*r.
+	for row := range rows {
+		for i := range 2*row + 1 {
+			cell := row*row + i
+			if i > 0 {
+				neighbors[cell] = append(neighbors[cell], cell-1)
+			}
+			if i < 2*row {
+				neighbors[cell] = append(neighbors[cell], cell+1)
+			}
+			switch {
+			case i%2 == 0 && row+1 < rows:
+				neighbors[cell] = append(neighbors[cell], (row+1)*(row+1)+i+1)
+			case i%2 == 1:
+				neighbors[cell] = append(neighbors[cell], (row-1)*(row-1)+i-1)
+			}
+		}
+	}
+	return NewBoard(neighbors)
+}
+
+// Size returns the number of cells on the board.
+func (b *Board) Size() int {
+	return len(b.neighbors)
+}
+
+// Neighbors returns the cells adjacent to cell, in ascending order.
+func (b *Board) Neighbors(cell int) []int {
+	return slices.Clone(b.neighbors[cell])
+}
+
+// distances returns the number of steps between every pair of cells, or -1
+// for cells that are not connected.
+func (b *Board) distances() [][]int {
+	dist := make([][]int, len(b.neighbors))
+	for start := range dist {
+		dist[start] = make([]int, len(b.neighbors))
+		for i := range dist[start] {
+			dist[start][i] = -1
+		}
+		dist[start][start] = 0
+		queue := []int{start}
+		for len(queue) > 0 {
+			cell := queue[0]
+			queue = queue[1:]
+			for _, n := range b.neighbors[cell] {
+				if dist[start][n] < 0 {
+					dist[start][n] = dist[start][cell] + 1
+					queue = append(queue, n)
+				}
+			}
+		}
+	}
+	return dist
+}
+
+// GraphMove slides the tile at cell From into the empty cell To.
+type GraphMove struct {
+	From, To int
+}
+
+func (m GraphMove) String() string {
+	return fmt.Sprintf("%d -> %d", m.From, m.To)
+}
+
+// GraphPuzzle is a sliding puzzle on an arbitrary Board. As with Puzzle, the
+// goal is for each tile value v to be in cell v.
+type GraphPuzzle struct {
+	board *Board
+	// cells holds the value of the tile in each cell.
+	cells     []int
+	emptyTile int
+	// emptyCell is the cell holding the empty tile.
+	emptyCell int
+}
+
+// NewGraphPuzzle returns a puzzle on board where cells[i] is the value of the
+// tile in cell i. The values must be a permutation of [0, board.Size()).
+func NewGraphPuzzle(board *Board, cells []int, emptyTileValue int) (*GraphPuzzle, error) {
+	if len(cells) != board.Size() {
+		return nil, &InvalidPuzzleError{fmt.Sprintf("board has %d cells; got %d values", board.Size(), len(cells))}
+	}
+	seen := make([]bool, len(cells))
+	emptyCell := -1
+	for cell, val := range cells {
+		if val < 0 || val >= len(cells) {
+			return nil, &InvalidPuzzleError{fmt.Sprintf("values must be in range [0, %d); got %d", len(cells), val)}
+		}
+		if seen[val] {
+			return nil, &InvalidPuzzleError{fmt.Sprintf("duplicate value %d", val)}
+		}
+		seen[val] = true
+		if val == emptyTileValue {
+			emptyCell = cell
+		}
+	}
+	if emptyCell < 0 {
+		return nil, &InvalidPuzzleError{fmt.Sprintf("empty tile value %d not found", emptyTileValue)}
+	}
+	return &GraphPuzzle{board: board, cells: slices.Clone(cells), emptyTile: emptyTileValue, emptyCell: emptyCell}, nil
+}
+
+// Board returns the board the puzzle is played on.
+func (p GraphPuzzle) Board() *Board {
+	return p.board
+}
+
+// Cells returns the value of the tile in each cell.
+func (p GraphPuzzle) Cells() []int {
+	return slices.Clone(p.cells)
+}
+
+func (p GraphPuzzle) String() string {
+	return fmt.Sprintf("cells=%v; empty=%d", p.cells, p.emptyTile)
+}
+
+// Moves returns every move possible from the current position: one for each
+// cell adjacent to the empty cell.
+func (p GraphPuzzle) Moves() []GraphMove {
+	moves := make([]GraphMove, 0, len(p.board.neighbors[p.emptyCell]))
+	for _, n := range p.board.neighbors[p.emptyCell] {
+		moves = append(moves, GraphMove{From: n, To: p.emptyCell})
+	}
+	return moves
+}
+
+// ApplyMove returns the puzzle after making move m. Like makeMove, it does not
+// modify p.
+func (p GraphPuzzle) ApplyMove(m GraphMove) (GraphPuzzle, error) {
+	if m.To != p.emptyCell || !slices.Contains(p.board.neighbors[m.To], m.From) {
+		return GraphPuzzle{}, &InvalidMoveError{fmt.Sprintf("cannot move %s from current position", m)}
+	}
+	next := GraphPuzzle{board: p.board, cells: slices.Clone(p.cells), emptyTile: p.emptyTile, emptyCell: m.From}
+	next.cells[m.To], next.cells[m.From] = next.cells[m.From], next.cells[m.To]
+	return next, nil
+}
+
+func (p GraphPuzzle) isSolved() bool {
+	for cell, val := range p.cells {
+		if cell != val {
+			return false
+		}
+	}
+	return true
+}
+
+func (p GraphPuzzle) key() string {
+	var b strings.Builder
+	for _, val := range p.cells {
+		b.WriteString(strconv.Itoa(val))
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+// Solve finds a solution with the fewest moves using breadth-first search.
+// There is no quick solvability check on arbitrary boards, so an unsolvable
+// puzzle is only detected once every reachable state has been searched.
+func (p GraphPuzzle) Solve() ([]GraphMove, error) {
+	if p.isSolved() {
+		return []GraphMove{}, nil
+	}
+
+	type state struct {
+		puzzle GraphPuzzle
+		moves  []GraphMove
+	}
+
+	queue := []state{{puzzle: p, moves: []GraphMove{}}}
+	visited := map[string]bool{p.key(): true}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, m := range current.puzzle.Moves() {
+			next, err := current.puzzle.ApplyMove(m)
+			if err != nil {
+				return nil, err
+			}
+
+			// Copy current moves and append this one.
+			newMoves := make([]GraphMove, len(current.moves)+1)
+			copy(newMoves, current.moves)
+			newMoves[len(current.moves)] = m
+			if next.isSolved() {
+				return newMoves, nil
+			}
+
+			key := next.key()
+			if !visited[key] {
+				visited[key] = true
+				queue = append(queue, state{puzzle: next, moves: newMoves})
+			}
+		}
+	}
+
+	return nil, UnsolvablePuzzleError{}
+}
+
+// graphDistance is the graph analogue of manhattanDistance: the sum over all
+// tiles except the empty one of the number of steps from the tile to its goal
+// cell. It returns -1 if some tile cannot reach its goal at all.
+func (p GraphPuzzle) graphDistance(dist [][]int) int {
+	total := 0
+	for cell, val := range p.cells {
+		if val == p.emptyTile {
+			continue
+		}
+		if dist[cell][val] < 0 {
+			return -1
+		}
+		total += dist[cell][val]
+	}
+	return total
+}
+
+// graphNode is a state reached by SolveAStar.
+type graphNode struct {
+	puzzle GraphPuzzle
+	g, h   int
+	parent *graphNode
+	move   GraphMove
+}
+
+// graphQueue is a priority queue of graph nodes ordered by g + h, breaking ties
+// in favor of deeper nodes.
+type graphQueue []*graphNode
+
+func (q graphQueue) Len() int { return len(q) }
+
+func (q graphQueue) Less(i, j int) bool {
+	fi, fj := q[i].g+q[i].h, q[j].g+q[j].h
+	if fi != fj {
+		return fi < fj
+	}
+	return q[i].g > q[j].g
+}
+
+func (q graphQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *graphQueue) Push(x any) { *q = append(*q, x.(*graphNode)) }
+
+func (q *graphQueue) Pop() any {
+	n := (*q)[len(*q)-1]
+	*q = (*q)[:len(*q)-1]
+	return n
+}
+
+// SolveAStar finds a solution with the fewest moves using A*. The heuristic is
+// the sum of each tile's shortest distance to its goal cell on the board,
+// which never overestimates since each move carries one tile one step.
+func (p GraphPuzzle) SolveAStar() ([]GraphMove, error) {
+	dist := p.board.distances()
+	h := p.graphDistance(dist)
+	if h < 0 {
+		return nil, UnsolvablePuzzleError{}
+	}
+	if p.isSolved() {
+		return []GraphMove{}, nil
+	}
+
+	open := &graphQueue{{puzzle: p, h: h}}
+	bestG := map[string]int{p.key(): 0}
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*graphNode)
+		if bestG[current.puzzle.key()] < current.g {
+			// A shorter path to this state was found after it was queued.
+			continue
+		}
+
+		if current.puzzle.isSolved() {
+			moves := []GraphMove{}
+			for node := current; node.parent != nil; node = node.parent {
+				moves = append(moves, node.move)
+			}
+			slices.Reverse(moves)
+			return moves, nil
+		}
+
+		for _, m := range current.puzzle.Moves() {
+			next, err := current.puzzle.ApplyMove(m)
+			if err != nil {
+				return nil, err
+			}
+			g := current.g + 1
+			key := next.key()
+			if best, seen := bestG[key]; seen && best <= g {
+				continue
+			}
+			bestG[key] = g
+			heap.Push(open, &graphNode{puzzle: next, g: g, h: next.graphDistance(dist), parent: current, move: m})
+		}
+	}
+
+	return nil, UnsolvablePuzzleError{}
+}
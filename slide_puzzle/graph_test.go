@@ -0,0 +1,206 @@
+package slide_puzzle
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewBoard(t *testing.T) {
+	for name, neighbors := range map[string][][]int{
+		"too few cells": {{}},
+		"out of range":  {{1}, {0, 2}},
+		"self loop":     {{0, 1}, {0}},
+		"asymmetric":    {{1}, {}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewBoard(neighbors)
+			var invalidErr *InvalidPuzzleError
+			if !errors.As(err, &invalidErr) {
+				t.Errorf("NewBoard(%v) error type = %T, want *InvalidPuzzleError", neighbors, err)
+			}
+		})
+	}
+}
+
+func TestBoardNeighbors(t *testing.T) {
+	tests := []struct {
+		name  string
+		board func() (*Board, error)
+		cell  int
+		want  []int
+	}{
+		{name: "grid corner", board: func() (*Board, error) { return GridBoard(3, 3) }, cell: 0, want: []int{1, 3}},
+		{name: "torus corner", board: func() (*Board, error) { return TorusBoard(3, 3) }, cell: 0, want: []int{1, 2, 3, 6}},
+		{name: "narrow torus", board: func() (*Board, error) { return TorusBoard(2, 3) }, cell: 0, want: []int{1, 2, 3}},
+		{name: "hex even row", board: func() (*Board, error) { return HexBoard(3, 3) }, cell: 1, want: []int{0, 2, 3, 4}},
+		{name: "hex odd row", board: func() (*Board, error) { return HexBoard(3, 3) }, cell: 4, want: []int{1, 2, 3, 5, 7, 8}},
+		{name: "triangle apex", board: func() (*Board, error) { return TriangleBoard(3) }, cell: 0, want: []int{2}},
+		{name: "triangle pointing down", board: func() (*Board, error) { return TriangleBoard(3) }, cell: 5, want: []int{1, 4, 6}},
+		{name: "triangle bottom row", board: func() (*Board, error) { return TriangleBoard(3) }, cell: 6, want: []int{5, 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.board()
+			if err != nil {
+				t.Fatalf("board error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, b.Neighbors(tt.cell)); diff != "" {
+				t.Errorf("Neighbors(%d) mismatch (-want +got):\n%s", tt.cell, diff)
+			}
+		})
+	}
+}
+
+// scrambleGraph makes n random moves from the goal state of board.
+func scrambleGraph(t *testing.T, rng *rand.Rand, board *Board, n int) GraphPuzzle {
+	t.Helper()
+	cells := make([]int, board.Size())
+	for i := range cells {
+		cells[i] = i
+	}
+	p, err := NewGraphPuzzle(board, cells, 0)
+	if err != nil {
+		t.Fatalf("NewGraphPuzzle() error: %v", err)
+	}
+	current := *p
+	for range n {
+		moves := current.Moves()
+		if current, err = current.ApplyMove(moves[rng.IntN(len(moves))]); err != nil {
+			t.Fatalf("ApplyMove() error: %v", err)
+		}
+	}
+	return current
+}
+
+// applyGraphMoves applies moves to p and fails the test if the result is not
+// solved.
+func applyGraphMoves(t *testing.T, p GraphPuzzle, moves []GraphMove) {
+	t.Helper()
+	for i, m := range moves {
+		var err error
+		if p, err = p.ApplyMove(m); err != nil {
+			t.Fatalf("applying move %d (%v) failed: %v", i, m, err)
+		}
+	}
+	if !p.isSolved() {
+		t.Fatalf("puzzle not solved after applying %d moves: %v", len(moves), p)
+	}
+}
+
+func TestGraphPuzzleSolve(t *testing.T) {
+	t.Run("grid board matches Puzzle", func(t *testing.T) {
+		grid := [][]int{{1, 2, 5}, {3, 4, 0}, {6, 7, 8}}
+		p, err := NewPuzzle(grid, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		want, err := p.Solve()
+		if err != nil {
+			t.Fatalf("Solve() error: %v", err)
+		}
+
+		board, err := GridBoard(3, 3)
+		if err != nil {
+			t.Fatalf("GridBoard() error: %v", err)
+		}
+		gp, err := NewGraphPuzzle(board, p.Values(), 0)
+		if err != nil {
+			t.Fatalf("NewGraphPuzzle() error: %v", err)
+		}
+		got, err := gp.Solve()
+		if err != nil {
+			t.Fatalf("Solve() error: %v", err)
+		}
+		applyGraphMoves(t, *gp, got)
+		if len(got) != len(want) {
+			t.Errorf("Solve() took %d moves, want %d", len(got), len(want))
+		}
+	})
+
+	t.Run("torus wraps around", func(t *testing.T) {
+		board, err := TorusBoard(2, 3)
+		if err != nil {
+			t.Fatalf("TorusBoard() error: %v", err)
+		}
+		p, err := NewGraphPuzzle(board, []int{2, 1, 0, 3, 4, 5}, 0)
+		if err != nil {
+			t.Fatalf("NewGraphPuzzle() error: %v", err)
+		}
+		got, err := p.Solve()
+		if err != nil {
+			t.Fatalf("Solve() error: %v", err)
+		}
+		if diff := cmp.Diff([]GraphMove{{From: 0, To: 2}}, got); diff != "" {
+			t.Errorf("Solve() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("A* matches BFS", func(t *testing.T) {
+		rng := rand.New(rand.NewPCG(4, 3))
+		for name, board := range map[string]func() (*Board, error){
+			"torus":    func() (*Board, error) { return TorusBoard(3, 3) },
+			"hex":      func() (*Board, error) { return HexBoard(3, 3) },
+			"triangle": func() (*Board, error) { return TriangleBoard(3) },
+		} {
+			b, err := board()
+			if err != nil {
+				t.Fatalf("%s board error: %v", name, err)
+			}
+			for range 3 {
+				p := scrambleGraph(t, rng, b, 30)
+				want, err := p.Solve()
+				if err != nil {
+					t.Fatalf("Solve() error for %v: %v", p, err)
+				}
+				got, err := p.SolveAStar()
+				if err != nil {
+					t.Fatalf("SolveAStar() error for %v: %v", p, err)
+				}
+				applyGraphMoves(t, p, got)
+				if len(got) != len(want) {
+					t.Errorf("%s: SolveAStar() took %d moves for %v, want %d", name, len(got), p, len(want))
+				}
+			}
+		}
+	})
+
+	t.Run("unsolvable puzzle returns error", func(t *testing.T) {
+		// Tiles on a path can never pass each other.
+		board, err := NewBoard([][]int{{1}, {0, 2}, {1}})
+		if err != nil {
+			t.Fatalf("NewBoard() error: %v", err)
+		}
+		p, err := NewGraphPuzzle(board, []int{0, 2, 1}, 0)
+		if err != nil {
+			t.Fatalf("NewGraphPuzzle() error: %v", err)
+		}
+		for name, solve := range map[string]func() ([]GraphMove, error){"Solve": p.Solve, "SolveAStar": p.SolveAStar} {
+			if _, err := solve(); !errors.As(err, &UnsolvablePuzzleError{}) {
+				t.Errorf("%s() error = %v, want UnsolvablePuzzleError", name, err)
+			}
+		}
+	})
+}
+
+func TestGraphPuzzleApplyMove(t *testing.T) {
+	board, err := TriangleBoard(2)
+	if err != nil {
+		t.Fatalf("TriangleBoard() error: %v", err)
+	}
+	p, err := NewGraphPuzzle(board, []int{0, 1, 2, 3}, 0)
+	if err != nil {
+		t.Fatalf("NewGraphPuzzle() error: %v", err)
+	}
+	if diff := cmp.Diff([]GraphMove{{From: 2, To: 0}}, p.Moves()); diff != "" {
+		t.Errorf("Moves() mismatch (-want +got):\n%s", diff)
+	}
+	_, err = p.ApplyMove(GraphMove{From: 1, To: 0})
+	var moveErr *InvalidMoveError
+	if !errors.As(err, &moveErr) {
+		t.Errorf("ApplyMove() error type = %T, want *InvalidMoveError", err)
+	}
+}
@@ -0,0 +1,249 @@
+package slide_puzzle
+
+import (
+	"container/heap"
+	"hash/maphash"
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// SolveHDAStar finds an optimal solution using hash-distributed A* with the
+// Manhattan distance heuristic. States are partitioned between the given
+// number of workers by hash; each worker owns the open and closed lists for
+// its states, and sends the states it generates to their owners. If workers is
+// not positive, one worker per CPU is used.
+//
+// Like Solve, it keeps every state it sees in memory, but it needs to explore
+// far fewer of them and spreads the work across all cores.
+func (p Puzzle) SolveHDAStar(workers int) ([]Move, error) {
+	if err := p.requireSingleEmpty(); err != nil {
+		return nil, err
+	}
+	if err := p.requireDistinct(); err != nil {
+		return nil, err
+	}
+	if !p.isSolvable() {
+		return nil, UnsolvablePuzzleError{}
+	}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	root := &hdaNode{board: newFlatPuzzle(p)}
+	if root.board.h == 0 {
+		return []Move{}, nil
+	}
+	root.key = root.board.key()
+
+	s := &hdaSearch{
+		seed:    maphash.MakeSeed(),
+		workers: make([]*hdaWorker, workers),
+		done:    make(chan struct{}),
+	}
+	s.bound.Store(math.MaxInt)
+	for i := range s.workers {
+		s.workers[i] = &hdaWorker{
+			search: s,
+			open:   &hdaQueue{},
+			closed: make(map[string]int),
+			notify: make(chan struct{}, 1),
+		}
+	}
+
+	s.send(root)
+	var wg sync.WaitGroup
+	for _, w := range s.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.run()
+		}()
+	}
+	wg.Wait()
+
+	if s.best == nil {
+		return nil, UnsolvablePuzzleError{}
+	}
+	return s.best.path(), nil
+}
+
+// hdaNode is a state generated by hash-distributed A*.
+type hdaNode struct {
+	board  *flatPuzzle
+	key    string
+	g      int
+	parent *hdaNode
+	move   Move
+}
+
+func (n *hdaNode) f() int { return n.g + n.board.h }
+
+// path returns the moves leading from the search root to n.
+func (n *hdaNode) path() []Move {
+	moves := make([]Move, n.g)
+	for node := n; node.parent != nil; node = node.parent {
+		moves[node.g-1] = node.move
+	}
+	return moves
+}
+
+// hdaSearch is the state shared by all workers.
+type hdaSearch struct {
+	seed    maphash.Seed
+	workers []*hdaWorker
+
+	// pending counts nodes that have been sent to a worker but not yet
+	// expanded or discarded. Every node in flight or in an open list is
+	// pending, and a node's children become pending before it stops being
+	// pending, so when the count reaches zero the search is over.
+	pending  atomic.Int64
+	done     chan struct{}
+	doneOnce sync.Once
+
+	mu sync.Mutex
+	// best is the shortest solution found so far.
+	best *hdaNode
+	// bound is the length of best, or math.MaxInt if there is none yet.
+	bound atomic.Int64
+}
+
+// send hands n to the worker that owns its state.
+func (s *hdaSearch) send(n *hdaNode) {
+	s.pending.Add(1)
+	owner := s.workers[maphash.String(s.seed, n.key)%uint64(len(s.workers))]
+	owner.mu.Lock()
+	owner.inbox = append(owner.inbox, n)
+	owner.mu.Unlock()
+	select {
+	case owner.notify <- struct{}{}:
+	default:
+		// The owner has already been notified.
+	}
+}
+
+// finish marks a node as no longer pending.
+func (s *hdaSearch) finish() {
+	if s.pending.Add(-1) == 0 {
+		s.doneOnce.Do(func() { close(s.done) })
+	}
+}
+
+func (s *hdaSearch) currentBound() int {
+	return int(s.bound.Load())
+}
+
+// offer records n as the best solution if it is shorter than the current one.
+func (s *hdaSearch) offer(n *hdaNode) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n.g < s.currentBound() {
+		s.best = n
+		s.bound.Store(int64(n.g))
+	}
+}
+
+// hdaWorker owns the open and closed lists for a partition of the states.
+type hdaWorker struct {
+	search *hdaSearch
+	open   *hdaQueue
+	// closed holds the shortest known depth of each owned state.
+	closed map[string]int
+
+	mu     sync.Mutex
+	inbox  []*hdaNode
+	notify chan struct{}
+}
+
+func (w *hdaWorker) run() {
+	s := w.search
+	for {
+		w.receive()
+
+		if w.open.Len() == 0 {
+			select {
+			case <-w.notify:
+				continue
+			case <-s.done:
+				return
+			}
+		}
+
+		n := heap.Pop(w.open).(*hdaNode)
+		// Skip nodes superseded by a shorter path, and nodes that cannot lead
+		// to a solution shorter than the best one found so far.
+		if w.closed[n.key] == n.g && n.f() < s.currentBound() {
+			w.expand(n)
+		}
+		s.finish()
+	}
+}
+
+// receive moves nodes from the inbox into the open list, discarding any that
+// are no better than what is already known.
+func (w *hdaWorker) receive() {
+	w.mu.Lock()
+	inbox := w.inbox
+	w.inbox = nil
+	w.mu.Unlock()
+
+	bound := w.search.currentBound()
+	for _, n := range inbox {
+		if g, seen := w.closed[n.key]; (seen && g <= n.g) || n.f() >= bound {
+			w.search.finish()
+			continue
+		}
+		w.closed[n.key] = n.g
+		heap.Push(w.open, n)
+	}
+}
+
+func (w *hdaWorker) expand(n *hdaNode) {
+	for _, m := range allMoves {
+		if n.parent != nil && m == n.move.opposite() {
+			continue
+		}
+		src := n.board.source(m)
+		if src < 0 {
+			continue
+		}
+		board := n.board.clone()
+		board.apply(src)
+		child := &hdaNode{board: board, g: n.g + 1, parent: n, move: m}
+
+		if board.h == 0 {
+			w.search.offer(child)
+			continue
+		}
+		if child.f() >= w.search.currentBound() {
+			continue
+		}
+		child.key = board.key()
+		w.search.send(child)
+	}
+}
+
+// hdaQueue is a priority queue of nodes ordered by g + h, breaking ties in
+// favor of deeper nodes.
+type hdaQueue []*hdaNode
+
+func (q hdaQueue) Len() int { return len(q) }
+
+func (q hdaQueue) Less(i, j int) bool {
+	if q[i].f() != q[j].f() {
+		return q[i].f() < q[j].f()
+	}
+	return q[i].g > q[j].g
+}
+
+func (q hdaQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *hdaQueue) Push(x any) { *q = append(*q, x.(*hdaNode)) }
+
+func (q *hdaQueue) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
@@ -0,0 +1,82 @@
+package slide_puzzle
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestSolveHDAStar(t *testing.T) {
+	rng := rand.New(rand.NewPCG(21, 22))
+
+	for _, workers := range []int{1, 3, 8} {
+		t.Run(fmt.Sprintf("optimal with %d workers", workers), func(t *testing.T) {
+			for range 5 {
+				puzzle, err := Scramble(2, 4, 7, 60, rng)
+				if err != nil {
+					t.Fatalf("Scramble() error: %v", err)
+				}
+				optimal, err := puzzle.Solve()
+				if err != nil {
+					t.Fatalf("Solve() error: %v", err)
+				}
+
+				got, err := puzzle.SolveHDAStar(workers)
+				if err != nil {
+					t.Fatalf("SolveHDAStar() error: %v", err)
+				}
+				assertSolves(t, *puzzle, got)
+				if len(got) != len(optimal) {
+					t.Errorf("SolveHDAStar() found %d moves, want %d", len(got), len(optimal))
+				}
+			}
+		})
+	}
+
+	t.Run("agrees with IDA* on 4x4", func(t *testing.T) {
+		puzzle, err := Scramble(4, 4, 0, 40, rng)
+		if err != nil {
+			t.Fatalf("Scramble() error: %v", err)
+		}
+		want, err := puzzle.SolveIDAStar(1)
+		if err != nil {
+			t.Fatalf("SolveIDAStar() error: %v", err)
+		}
+		got, err := puzzle.SolveHDAStar(4)
+		if err != nil {
+			t.Fatalf("SolveHDAStar() error: %v", err)
+		}
+		assertSolves(t, *puzzle, got)
+		if len(got) != len(want) {
+			t.Errorf("SolveHDAStar() found %d moves, want %d", len(got), len(want))
+		}
+	})
+
+	t.Run("already solved puzzle", func(t *testing.T) {
+		puzzle, err := NewPuzzle(goalGrid(3, 3), 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		got, err := puzzle.SolveHDAStar(2)
+		if err != nil {
+			t.Fatalf("SolveHDAStar() error: %v", err)
+		}
+		if len(got) != 0 {
+			t.Fatalf("SolveHDAStar() returned %d moves, want 0", len(got))
+		}
+	})
+
+	t.Run("unsolvable puzzle returns error", func(t *testing.T) {
+		grid := goalGrid(3, 3)
+		grid[2][1], grid[2][2] = grid[2][2], grid[2][1]
+		puzzle, err := NewPuzzle(grid, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		_, err = puzzle.SolveHDAStar(2)
+		if !errors.As(err, &UnsolvablePuzzleError{}) {
+			t.Fatalf("SolveHDAStar() error = %v, want UnsolvablePuzzleError", err)
+		}
+	})
+}
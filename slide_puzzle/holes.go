@@ -0,0 +1,219 @@
+package slide_puzzle
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Slide moves a tile one step in direction Dir into an adjacent empty tile. It
+// is the move type for puzzles with several empty tiles, where a direction
+// alone does not say which tile moves.
+type Slide struct {
+	// Tile is the value of the tile that moves.
+	Tile int
+	// Hole is the value of the empty tile it moves into.
+	Hole int
+	Dir  Move
+}
+
+func (s Slide) String() string {
+	return fmt.Sprintf("%d %s into %d", s.Tile, s.Dir, s.Hole)
+}
+
+// Format writes s in convention c. With TileMoves it is the same as String;
+// with BlankMoves it names the empty tile and the direction it moves, e.g.
+// "empty 0 South to 5" for "5 North into 0".
+func (s Slide) Format(c Convention) string {
+	if c == BlankMoves {
+		return fmt.Sprintf("empty %d %s to %d", s.Hole, s.Dir.Convert(TileMoves, c), s.Tile)
+	}
+	return s.String()
+}
+
+// NewPuzzleWithHoles is like NewPuzzle, but any number of values may be empty
+// tiles. The empty tiles are interchangeable: the puzzle is solved when every
+// other tile is at its goal position, whichever empty tile fills each of the
+// remaining positions.
+//
+// Only SolveHoles can solve puzzles with more than one empty tile.
+func NewPuzzleWithHoles(grid [][]int, emptyTileValues []int) (*Puzzle, error) {
+	if len(emptyTileValues) == 0 {
+		return nil, &InvalidPuzzleError{"puzzle must have at least one empty tile"}
+	}
+	p, err := NewPuzzle(grid, emptyTileValues[0])
+	if err != nil {
+		return nil, err
+	}
+
+	// NewPuzzle has checked that the grid holds each value exactly once.
+	numTiles := len(grid) * len(grid[0])
+	for i, val := range emptyTileValues[1:] {
+		if val < 0 || val >= numTiles {
+			return nil, &InvalidPuzzleError{fmt.Sprintf("empty tile values must be in range [0, %d); got %d", numTiles, val)}
+		}
+		if slices.Contains(emptyTileValues[:i+1], val) {
+			return nil, &InvalidPuzzleError{fmt.Sprintf("duplicate empty tile value %d", val)}
+		}
+		found := false
+		for row := range grid {
+			if col := slices.Index(grid[row], val); col >= 0 {
+				p.holes = append(p.holes, tile{value: val, coord: coord{row: row, col: col}})
+				found = true
+			}
+		}
+		if !found {
+			// Only possible when the value's goal cell is blocked.
+			return nil, &InvalidPuzzleError{fmt.Sprintf("empty tile value %d is not in the grid", val)}
+		}
+	}
+	return p, nil
+}
+
+// EmptyValues returns the values of the puzzle's empty tiles.
+func (p Puzzle) EmptyValues() []int {
+	values := []int{p.emptyTile.value}
+	for _, h := range p.holes {
+		values = append(values, h.value)
+	}
+	return values
+}
+
+// isEmpty reports whether val is the value of an empty tile.
+func (p Puzzle) isEmpty(val int) bool {
+	if val == p.emptyTile.value {
+		return true
+	}
+	for _, h := range p.holes {
+		if val == h.value {
+			return true
+		}
+	}
+	return false
+}
+
+// requireSingleEmpty returns an error if p has more than one empty tile, for
+// solvers that only handle one.
+func (p Puzzle) requireSingleEmpty() error {
+	if len(p.holes) > 0 {
+		return &InvalidPuzzleError{fmt.Sprintf(
+			"this solver supports a single empty tile; puzzle has %d, use SolveHoles", len(p.holes)+1,
+		)}
+	}
+	return nil
+}
+
+// emptyTiles returns pointers to all of p's empty tiles, starting with
+// p.emptyTile.
+func (p *Puzzle) emptyTiles() []*tile {
+	tiles := []*tile{&p.emptyTile}
+	for i := range p.holes {
+		tiles = append(tiles, &p.holes[i])
+	}
+	return tiles
+}
+
+// Slides returns every slide possible from the current position. Tiles never
+// slide into an empty tile from another empty tile.
+func (p Puzzle) Slides() []Slide {
+	var slides []Slide
+	for _, hole := range p.emptyTiles() {
+		for _, dir := range allMoves {
+			src := dir.source(hole.coord)
+			if !p.isOpen(src) {
+				continue
+			}
+			if val := p.grid[src.row][src.col]; !p.isEmpty(val) {
+				slides = append(slides, Slide{Tile: val, Hole: hole.value, Dir: dir})
+			}
+		}
+	}
+	return slides
+}
+
+// ApplySlide returns the puzzle after making slide s. Like makeMove, it does
+// not modify p.
+func (p Puzzle) ApplySlide(s Slide) (Puzzle, error) {
+	if !slices.Contains(p.Slides(), s) {
+		return Puzzle{}, &InvalidMoveError{fmt.Sprintf("cannot slide %s from current position", s)}
+	}
+
+	next := Puzzle{
+		grid:      cloneGrid(p.grid),
+		emptyTile: p.emptyTile,
+		holes:     slices.Clone(p.holes),
+		colors:    p.colors,
+		wildcards: p.wildcards,
+	}
+	for _, hole := range next.emptyTiles() {
+		if hole.value != s.Hole {
+			continue
+		}
+		src := s.Dir.source(hole.coord)
+		next.grid[hole.coord.row][hole.coord.col] = s.Tile
+		next.grid[src.row][src.col] = hole.value
+		hole.coord = src
+	}
+	return next, nil
+}
+
+// holesKey identifies the arrangement of p's tiles, treating all empty tiles
+// as the same.
+func (p Puzzle) holesKey() string {
+	var b strings.Builder
+	for _, val := range p.Values() {
+		if p.isEmpty(val) {
+			val = -1
+		}
+		b.WriteString(strconv.Itoa(val))
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+// SolveHoles finds a solution with the fewest slides for a puzzle with any
+// number of empty tiles, using breadth-first search. Since the empty tiles are
+// interchangeable, states that differ only in which empty tile is where are
+// only searched once.
+func (p Puzzle) SolveHoles() ([]Slide, error) {
+	if p.isSolved() {
+		return []Slide{}, nil
+	}
+
+	type state struct {
+		puzzle Puzzle
+		slides []Slide
+	}
+
+	queue := []state{{puzzle: p, slides: []Slide{}}}
+	visited := map[string]bool{p.holesKey(): true}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, s := range current.puzzle.Slides() {
+			next, err := current.puzzle.ApplySlide(s)
+			if err != nil {
+				return nil, err
+			}
+
+			// Copy current slides and append this one.
+			newSlides := make([]Slide, len(current.slides)+1)
+			copy(newSlides, current.slides)
+			newSlides[len(current.slides)] = s
+			if next.isSolved() {
+				return newSlides, nil
+			}
+
+			key := next.holesKey()
+			if !visited[key] {
+				visited[key] = true
+				queue = append(queue, state{puzzle: next, slides: newSlides})
+			}
+		}
+	}
+
+	return nil, UnsolvablePuzzleError{}
+}
@@ -0,0 +1,180 @@
+package slide_puzzle
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewPuzzleWithHoles(t *testing.T) {
+	t.Run("valid puzzle", func(t *testing.T) {
+		p, err := NewPuzzleWithHoles([][]int{{1, 0, 2}, {3, 5, 4}}, []int{0, 5})
+		if err != nil {
+			t.Fatalf("NewPuzzleWithHoles() error: %v", err)
+		}
+		if diff := cmp.Diff([]int{0, 5}, p.EmptyValues()); diff != "" {
+			t.Errorf("EmptyValues() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	for name, values := range map[string][]int{
+		"no empty tiles":  {},
+		"out of range":    {0, 6},
+		"duplicate value": {0, 0},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewPuzzleWithHoles([][]int{{1, 0, 2}, {3, 5, 4}}, values)
+			var invalidErr *InvalidPuzzleError
+			if !errors.As(err, &invalidErr) {
+				t.Errorf("NewPuzzleWithHoles(%v) error type = %T, want *InvalidPuzzleError", values, err)
+			}
+		})
+	}
+
+	t.Run("value not in grid", func(t *testing.T) {
+		// 4 belongs in the blocked cell, so the grid cannot hold it.
+		_, err := NewPuzzleWithHoles([][]int{{1, 0, 2}, {3, Blocked, 5}}, []int{0, 4})
+		var invalidErr *InvalidPuzzleError
+		if !errors.As(err, &invalidErr) {
+			t.Errorf("NewPuzzleWithHoles() error type = %T, want *InvalidPuzzleError", err)
+		}
+	})
+}
+
+// applySlides applies slides to p and fails the test if the result is not
+// solved.
+func TestSlideFormat(t *testing.T) {
+	s := Slide{Tile: 5, Hole: 0, Dir: North}
+	if got, want := s.Format(TileMoves), "5 North into 0"; got != want {
+		t.Errorf("Format(TileMoves) = %q, want %q", got, want)
+	}
+	if got, want := s.Format(BlankMoves), "empty 0 South to 5"; got != want {
+		t.Errorf("Format(BlankMoves) = %q, want %q", got, want)
+	}
+}
+
+func applySlides(t *testing.T, p Puzzle, slides []Slide) {
+	t.Helper()
+	for i, s := range slides {
+		var err error
+		if p, err = p.ApplySlide(s); err != nil {
+			t.Fatalf("applying slide %d (%v) failed: %v", i, s, err)
+		}
+	}
+	if !p.isSolved() {
+		t.Fatalf("puzzle not solved after applying %d slides: %v", len(slides), p)
+	}
+}
+
+func TestSolveHoles(t *testing.T) {
+	t.Run("empty tiles are interchangeable", func(t *testing.T) {
+		p, err := NewPuzzleWithHoles([][]int{{0, 1, 2}, {3, 5, 4}}, []int{4, 5})
+		if err != nil {
+			t.Fatalf("NewPuzzleWithHoles() error: %v", err)
+		}
+		if !p.isSolved() {
+			t.Errorf("isSolved() = false for %v", p)
+		}
+	})
+
+	t.Run("two holes beat one", func(t *testing.T) {
+		// The tile 1 can step around 2 using the second hole.
+		grid := [][]int{{0, 2, 1}, {3, 4, 5}}
+		p, err := NewPuzzleWithHoles(grid, []int{4, 5})
+		if err != nil {
+			t.Fatalf("NewPuzzleWithHoles() error: %v", err)
+		}
+		slides, err := p.SolveHoles()
+		if err != nil {
+			t.Fatalf("SolveHoles() error: %v", err)
+		}
+		applySlides(t, *p, slides)
+
+		// With one empty tile, swapping two tiles is impossible.
+		single, err := NewPuzzle(grid, 4)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		if _, err := single.Solve(); !errors.As(err, &UnsolvablePuzzleError{}) {
+			t.Errorf("Solve() error = %v, want UnsolvablePuzzleError", err)
+		}
+	})
+
+	t.Run("single hole matches Solve", func(t *testing.T) {
+		p, err := NewPuzzleWithHoles([][]int{{1, 4, 2}, {3, 0, 5}}, []int{0})
+		if err != nil {
+			t.Fatalf("NewPuzzleWithHoles() error: %v", err)
+		}
+		want, err := p.Solve()
+		if err != nil {
+			t.Fatalf("Solve() error: %v", err)
+		}
+		got, err := p.SolveHoles()
+		if err != nil {
+			t.Fatalf("SolveHoles() error: %v", err)
+		}
+		applySlides(t, *p, got)
+		if len(got) != len(want) {
+			t.Errorf("SolveHoles() took %d slides, want %d", len(got), len(want))
+		}
+	})
+
+	t.Run("invalid slide returns error", func(t *testing.T) {
+		p, err := NewPuzzleWithHoles([][]int{{0, 1, 2}, {3, 4, 5}}, []int{0, 5})
+		if err != nil {
+			t.Fatalf("NewPuzzleWithHoles() error: %v", err)
+		}
+		_, err = p.ApplySlide(Slide{Tile: 4, Hole: 0, Dir: North})
+		var moveErr *InvalidMoveError
+		if !errors.As(err, &moveErr) {
+			t.Errorf("ApplySlide() error type = %T, want *InvalidMoveError", err)
+		}
+	})
+
+	t.Run("moves keep colors, wildcards and holes", func(t *testing.T) {
+		parse := func(text string) Puzzle {
+			t.Helper()
+			var p Puzzle
+			if err := p.UnmarshalText([]byte(text)); err != nil {
+				t.Fatalf("UnmarshalText(%q) error: %v", text, err)
+			}
+			return p
+		}
+		p := parse("grid=1 2 3/4 0 5; empty=0,5; colors=0 1 1/2 2 0; wildcards=3")
+
+		slid, err := p.ApplySlide(Slide{Tile: 2, Hole: 0, Dir: South})
+		if err != nil {
+			t.Fatalf("ApplySlide() error: %v", err)
+		}
+		if want := parse("grid=1 0 3/4 2 5; empty=0,5; colors=0 1 1/2 2 0; wildcards=3"); !slid.Equal(want) {
+			t.Errorf("ApplySlide() = %v, want %v", slid, want)
+		}
+
+		// Moving the other empty tile with makeMove must keep track of it.
+		moved, err := p.makeMove(West)
+		if err != nil {
+			t.Fatalf("makeMove() error: %v", err)
+		}
+		if want := parse("grid=1 2 3/4 5 0; empty=0,5; colors=0 1 1/2 2 0; wildcards=3"); !moved.Equal(want) {
+			t.Errorf("makeMove() = %v, want %v", moved, want)
+		}
+		if _, err := moved.ApplySlide(Slide{Tile: 2, Hole: 5, Dir: South}); err != nil {
+			t.Errorf("ApplySlide() after makeMove() error: %v", err)
+		}
+	})
+
+	t.Run("other solvers reject several holes", func(t *testing.T) {
+		p, err := NewPuzzleWithHoles([][]int{{0, 1, 2}, {3, 4, 5}}, []int{0, 5})
+		if err != nil {
+			t.Fatalf("NewPuzzleWithHoles() error: %v", err)
+		}
+		var invalidErr *InvalidPuzzleError
+		if _, err := p.Solve(); !errors.As(err, &invalidErr) {
+			t.Errorf("Solve() error type = %T, want *InvalidPuzzleError", err)
+		}
+		if _, err := p.SolveIDAStar(1); !errors.As(err, &invalidErr) {
+			t.Errorf("SolveIDAStar() error type = %T, want *InvalidPuzzleError", err)
+		}
+	})
+}
@@ -0,0 +1,249 @@
+package slide_puzzle
+
+import (
+	"errors"
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// idaSubtreesPerWorker is roughly how many subtrees the search tree is split
+// into per worker, so that workers that finish early can pick up more work.
+const idaSubtreesPerWorker = 16
+
+// errSearchCanceled is returned by solveIDAStar when it is canceled before
+// finding a solution.
+var errSearchCanceled = errors.New("search canceled")
+
+// SolveIDAStar finds an optimal solution using iterative deepening A* with the
+// Manhattan distance heuristic. It uses far less memory than Solve, which makes
+// it practical for boards like the 4x4 15-puzzle.
+//
+// The tree below the root is split into subtrees that are searched in parallel
+// by the given number of workers, each taking the next unsearched subtree from
+// a shared queue when it finishes one. If workers is not positive, one worker
+// per CPU is used.
+//
+// Blocked boards whose Solvability is SolvabilityUnknown are rejected, since
+// the search could not tell that such a puzzle is unsolvable.
+func (p Puzzle) SolveIDAStar(workers int) ([]Move, error) {
+	return p.solveIDAStar(workers, nil, nil)
+}
+
+// SolveIDAStarPDB is like SolveIDAStar, but uses the larger of the Manhattan
+// distance and the pattern database lookup as the heuristic. A good pattern
+// database cuts the number of states searched by orders of magnitude.
+func (p Puzzle) SolveIDAStarPDB(db *PatternDatabase, workers int) ([]Move, error) {
+	if err := db.check(p); err != nil {
+		return nil, err
+	}
+	return p.solveIDAStar(workers, db, nil)
+}
+
+// solveIDAStar is SolveIDAStar with an optional pattern database and an
+// optional cancel flag, which stops the search with errSearchCanceled once it
+// is set.
+func (p Puzzle) solveIDAStar(workers int, db *PatternDatabase, cancel *atomic.Bool) ([]Move, error) {
+	if err := p.requireSingleEmpty(); err != nil {
+		return nil, err
+	}
+	if err := p.requireDistinct(); err != nil {
+		return nil, err
+	}
+	if !p.isSolvable() {
+		return nil, UnsolvablePuzzleError{}
+	}
+	if p.Solvability() == SolvabilityUnknown {
+		// Without duplicate detection, the search would never finish on an
+		// unsolvable puzzle, since the states form cycles.
+		return nil, &InvalidPuzzleError{"cannot tell whether this blocked board is solvable; use Solve or SolveHDAStar"}
+	}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	root := idaSubtree{board: newFlatPuzzle(p)}
+	if root.board.h == 0 {
+		return []Move{}, nil
+	}
+
+	subtrees, solution := splitSubtrees(root, workers*idaSubtreesPerWorker)
+	if solution != nil {
+		return solution, nil
+	}
+
+	threshold := estimate(root.board, db)
+	for {
+		moves, next := searchSubtrees(subtrees, threshold, workers, db, cancel)
+		if moves != nil {
+			return moves, nil
+		}
+		if cancel != nil && cancel.Load() {
+			return nil, errSearchCanceled
+		}
+		if next == math.MaxInt {
+			// Not reachable for solvable puzzles, but don't loop forever.
+			return nil, UnsolvablePuzzleError{}
+		}
+		threshold = next
+	}
+}
+
+// estimate returns a lower bound on the number of moves needed to solve
+// board: its Manhattan distance, or the pattern database lookup if that is
+// larger.
+func estimate(board *flatPuzzle, db *PatternDatabase) int {
+	if db == nil {
+		return board.h
+	}
+	return max(board.h, db.lookup(board.cells))
+}
+
+// idaSubtree is the root of a part of the search tree, along with the moves
+// that lead to it from the starting puzzle.
+type idaSubtree struct {
+	board *flatPuzzle
+	moves []Move
+}
+
+// splitSubtrees expands the search tree breadth-first until there are at least
+// n subtrees. If a solution is found while doing so, it is returned instead;
+// since the tree is expanded one depth at a time, it is optimal.
+func splitSubtrees(root idaSubtree, n int) ([]idaSubtree, []Move) {
+	// Stop splitting at a modest depth so tiny boards do not explode.
+	const maxDepth = 12
+
+	level := []idaSubtree{root}
+	for depth := 0; len(level) < n && depth < maxDepth; depth++ {
+		var next []idaSubtree
+		for _, st := range level {
+			for _, m := range allMoves {
+				if len(st.moves) > 0 && m == st.moves[len(st.moves)-1].opposite() {
+					continue
+				}
+				src := st.board.source(m)
+				if src < 0 {
+					continue
+				}
+				child := idaSubtree{
+					board: st.board.clone(),
+					moves: append(append([]Move(nil), st.moves...), m),
+				}
+				child.board.apply(src)
+				if child.board.h == 0 {
+					return nil, child.moves
+				}
+				next = append(next, child)
+			}
+		}
+		level = next
+	}
+	return level, nil
+}
+
+// searchSubtrees runs one iteration of IDA* with the given cost threshold over
+// all subtrees. It returns a solution if one was found, and otherwise the
+// smallest cost that exceeded the threshold, to use as the next threshold.
+//
+// Any solution found is optimal: every solution no longer than the previous
+// threshold was ruled out in earlier iterations, and this threshold is the
+// smallest cost seen above it.
+func searchSubtrees(subtrees []idaSubtree, threshold, workers int, db *PatternDatabase, cancel *atomic.Bool) ([]Move, int) {
+	queue := make(chan idaSubtree, len(subtrees))
+	for _, st := range subtrees {
+		queue <- st
+	}
+	close(queue)
+
+	var (
+		mu       sync.Mutex
+		solution []Move
+		next     = math.MaxInt
+		found    atomic.Bool
+		wg       sync.WaitGroup
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := idaWorker{threshold: threshold, next: math.MaxInt, stop: &found, cancel: cancel, db: db}
+			for st := range queue {
+				if w.stopped() {
+					break
+				}
+				board := st.board.clone()
+				path := append([]Move(nil), st.moves...)
+				if w.search(board, &path) {
+					mu.Lock()
+					if solution == nil {
+						solution = path
+					}
+					mu.Unlock()
+					found.Store(true)
+					break
+				}
+			}
+			mu.Lock()
+			next = min(next, w.next)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return solution, next
+}
+
+// idaWorker runs depth-first searches bounded by a cost threshold.
+type idaWorker struct {
+	threshold int
+	// next is the smallest cost seen that exceeded the threshold.
+	next int
+	// stop is set when any worker finds a solution.
+	stop *atomic.Bool
+	// cancel, if not nil, is set to give up on the search.
+	cancel *atomic.Bool
+	// db, if not nil, improves on the Manhattan distance.
+	db *PatternDatabase
+}
+
+func (w *idaWorker) stopped() bool {
+	return w.stop.Load() || w.cancel != nil && w.cancel.Load()
+}
+
+// search looks for a solution below board within the threshold, extending
+// path with the moves taken. The board is restored before returning unless a
+// solution is found.
+func (w *idaWorker) search(board *flatPuzzle, path *[]Move) bool {
+	g := len(*path)
+	f := g + estimate(board, w.db)
+	if f > w.threshold {
+		w.next = min(w.next, f)
+		return false
+	}
+	if board.h == 0 {
+		return true
+	}
+	if w.stopped() {
+		return false
+	}
+
+	for _, m := range allMoves {
+		// Never undo the previous move.
+		if g > 0 && m == (*path)[g-1].opposite() {
+			continue
+		}
+		src := board.source(m)
+		if src < 0 {
+			continue
+		}
+		empty := board.empty
+		board.apply(src)
+		*path = append(*path, m)
+		if w.search(board, path) {
+			return true
+		}
+		*path = (*path)[:g]
+		board.apply(empty)
+	}
+	return false
+}
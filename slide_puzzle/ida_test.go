@@ -0,0 +1,143 @@
+package slide_puzzle
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func TestSolveIDAStar(t *testing.T) {
+	rng := rand.New(rand.NewPCG(11, 12))
+
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("optimal with %d workers", workers), func(t *testing.T) {
+			for range 5 {
+				puzzle, err := Scramble(2, 4, 0, 60, rng)
+				if err != nil {
+					t.Fatalf("Scramble() error: %v", err)
+				}
+				optimal, err := puzzle.Solve()
+				if err != nil {
+					t.Fatalf("Solve() error: %v", err)
+				}
+
+				got, err := puzzle.SolveIDAStar(workers)
+				if err != nil {
+					t.Fatalf("SolveIDAStar() error: %v", err)
+				}
+				assertSolves(t, *puzzle, got)
+				if len(got) != len(optimal) {
+					t.Errorf("SolveIDAStar() found %d moves, want %d", len(got), len(optimal))
+				}
+			}
+		})
+	}
+
+	t.Run("4x4 serial and parallel agree", func(t *testing.T) {
+		puzzle, err := Scramble(4, 4, 0, 40, rng)
+		if err != nil {
+			t.Fatalf("Scramble() error: %v", err)
+		}
+		serial, err := puzzle.SolveIDAStar(1)
+		if err != nil {
+			t.Fatalf("SolveIDAStar(1) error: %v", err)
+		}
+		parallel, err := puzzle.SolveIDAStar(0)
+		if err != nil {
+			t.Fatalf("SolveIDAStar(0) error: %v", err)
+		}
+		assertSolves(t, *puzzle, parallel)
+		if len(serial) != len(parallel) {
+			t.Errorf("serial found %d moves, parallel found %d", len(serial), len(parallel))
+		}
+	})
+
+	t.Run("solution shallower than the split depth", func(t *testing.T) {
+		grid := [][]int{
+			{1, 0, 2},
+			{3, 4, 5},
+			{6, 7, 8},
+		}
+		puzzle, err := NewPuzzle(grid, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		got, err := puzzle.SolveIDAStar(8)
+		if err != nil {
+			t.Fatalf("SolveIDAStar() error: %v", err)
+		}
+		if diff := len(got); diff != 1 {
+			t.Fatalf("SolveIDAStar() found %d moves, want 1", diff)
+		}
+	})
+
+	t.Run("already solved puzzle", func(t *testing.T) {
+		puzzle, err := NewPuzzle(goalGrid(3, 3), 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		got, err := puzzle.SolveIDAStar(2)
+		if err != nil {
+			t.Fatalf("SolveIDAStar() error: %v", err)
+		}
+		if len(got) != 0 {
+			t.Fatalf("SolveIDAStar() returned %d moves, want 0", len(got))
+		}
+	})
+
+	t.Run("unsolvable puzzle returns error", func(t *testing.T) {
+		grid := goalGrid(3, 3)
+		grid[2][1], grid[2][2] = grid[2][2], grid[2][1]
+		puzzle, err := NewPuzzle(grid, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		_, err = puzzle.SolveIDAStar(2)
+		if !errors.As(err, &UnsolvablePuzzleError{}) {
+			t.Fatalf("SolveIDAStar() error = %v, want UnsolvablePuzzleError", err)
+		}
+	})
+
+	t.Run("blocked board of unknown solvability returns error", func(t *testing.T) {
+		puzzle, err := NewPuzzle([][]int{{3, 4, Blocked, 9, 0}, {1, 8, 6, 5, 7}}, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		if got := puzzle.Solvability(); got != SolvabilityUnknown {
+			t.Fatalf("Solvability() = %v, want %v", got, SolvabilityUnknown)
+		}
+		// Before this was rejected, the search never finished.
+		done := make(chan error, 1)
+		go func() {
+			_, err := puzzle.SolveIDAStar(2)
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			var invalidErr *InvalidPuzzleError
+			if !errors.As(err, &invalidErr) {
+				t.Errorf("SolveIDAStar() error type = %T, want *InvalidPuzzleError", err)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("SolveIDAStar() did not return")
+		}
+	})
+}
+
+func BenchmarkSolveIDAStar(b *testing.B) {
+	puzzle, err := Scramble(4, 4, 0, 300, rand.New(rand.NewPCG(1, 2)))
+	if err != nil {
+		b.Fatalf("Scramble() error: %v", err)
+	}
+	for _, workers := range []int{1, 0} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := puzzle.SolveIDAStar(workers); err != nil {
+					b.Fatalf("SolveIDAStar() error: %v", err)
+				}
+			}
+		})
+	}
+}
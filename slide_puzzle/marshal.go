@@ -0,0 +1,387 @@
+package slide_puzzle
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// puzzleVersion starts every puzzle in binary form, so the format can change
+// without misreading stored puzzles.
+const puzzleVersion = 1
+
+// MarshalText encodes p as a single line of text, such as
+//
+//	grid=1 2 3/4 0 5/# 6 7; empty=0
+//
+// The grid lists the tile values of each row, separated by spaces, with rows
+// separated by slashes and "#" marking blocked cells. After it come the
+// values of the empty tiles, separated by commas. Colored puzzles also have
+// colors=, giving the color of the tile with each value laid out like the
+// grid, and wildcards=, listing the cells whose goal accepts any tile in
+// row-major order. Empty tiles are listed in increasing order, whatever order
+// they were given in.
+//
+// The zero Puzzle, which has no cells, is encoded as empty text.
+func (p Puzzle) MarshalText() ([]byte, error) {
+	if p.Rows() == 0 {
+		return []byte{}, nil
+	}
+	fields := []string{
+		"grid=" + formatCells(p.Values(), p.Cols()),
+		"empty=" + joinInts(p.sortedEmptyValues()),
+	}
+	if p.colors != nil {
+		fields = append(fields, "colors="+formatCells(p.colors, p.Cols()))
+	}
+	if cells := p.wildcardCells(); len(cells) > 0 {
+		fields = append(fields, "wildcards="+joinInts(cells))
+	}
+	return []byte(strings.Join(fields, "; ")), nil
+}
+
+// UnmarshalText decodes a puzzle encoded by MarshalText, checking it as
+// NewPuzzleWithHoles and NewColoredPuzzle do.
+func (p *Puzzle) UnmarshalText(text []byte) error {
+	if len(strings.TrimSpace(string(text))) == 0 {
+		*p = Puzzle{}
+		return nil
+	}
+	var grid, colors [][]int
+	var empty, wildcards []int
+	seen := map[string]bool{}
+	for field := range strings.SplitSeq(string(text), ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok {
+			return &InvalidPuzzleError{fmt.Sprintf("invalid puzzle field %q; want key=value", field)}
+		}
+		if seen[key] {
+			return &InvalidPuzzleError{fmt.Sprintf("puzzle field %q given twice", key)}
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "grid":
+			grid, err = parseCells(value)
+		case "empty":
+			empty, err = splitInts(value)
+		case "colors":
+			colors, err = parseCells(value)
+		case "wildcards":
+			wildcards, err = splitInts(value)
+		default:
+			return &InvalidPuzzleError{fmt.Sprintf("unknown puzzle field %q", key)}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if grid == nil || empty == nil {
+		return &InvalidPuzzleError{"puzzle text must have grid= and empty= fields"}
+	}
+
+	decoded, err := buildPuzzle(grid, empty, slices.Concat(colors...), wildcards)
+	if err != nil {
+		return err
+	}
+	*p = *decoded
+	return nil
+}
+
+// MarshalBinary encodes p compactly. The format is a version byte, the number
+// of rows and columns, the tile values in row-major order, the empty tile
+// values, the colors (if any) and the wildcard cells, with each list preceded
+// by its length and every number written as a varint. As in MarshalText, the
+// empty tile values are sorted.
+//
+// The zero Puzzle is encoded as the version byte followed by 0 rows and 0
+// columns.
+func (p Puzzle) MarshalBinary() ([]byte, error) {
+	b := []byte{puzzleVersion}
+	if p.Rows() == 0 {
+		return binary.AppendUvarint(binary.AppendUvarint(b, 0), 0), nil
+	}
+	b = binary.AppendUvarint(b, uint64(p.Rows()))
+	b = binary.AppendUvarint(b, uint64(p.Cols()))
+	b = appendVarints(b, p.Values())
+	b = appendVarints(b, p.sortedEmptyValues())
+	b = appendVarints(b, p.colors)
+	b = appendVarints(b, p.wildcardCells())
+	return b, nil
+}
+
+// UnmarshalBinary decodes a puzzle encoded by MarshalBinary, checking it as
+// UnmarshalText does.
+func (p *Puzzle) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != puzzleVersion {
+		return &InvalidPuzzleError{"not a puzzle in a supported binary format"}
+	}
+	d := varintDecoder{data: data[1:]}
+	rows, cols := d.uvarint(), d.uvarint()
+	if d.err == nil && rows == 0 && cols == 0 && len(d.data) == 0 {
+		*p = Puzzle{}
+		return nil
+	}
+	if n := uint64(len(d.data)); d.err == nil && (rows == 0 || cols == 0 || rows > n || cols > n || rows*cols > n) {
+		// Every cell takes at least a byte, which also bounds the allocation.
+		return &InvalidPuzzleError{fmt.Sprintf("invalid puzzle dimensions %dx%d", rows, cols)}
+	}
+	values := d.varints()
+	empty := d.varints()
+	colors := d.varints()
+	wildcards := d.varints()
+	if d.err != nil {
+		return d.err
+	}
+	if len(d.data) > 0 {
+		return &InvalidPuzzleError{"unexpected data after puzzle"}
+	}
+	if len(values) != int(rows*cols) {
+		return &InvalidPuzzleError{fmt.Sprintf("%dx%d puzzle has %d values", rows, cols, len(values))}
+	}
+
+	grid := make([][]int, rows)
+	for row := range grid {
+		grid[row] = values[row*int(cols) : (row+1)*int(cols)]
+	}
+	decoded, err := buildPuzzle(grid, empty, colors, wildcards)
+	if err != nil {
+		return err
+	}
+	*p = *decoded
+	return nil
+}
+
+// Equal reports whether p and q are the same puzzle: the same tiles in the
+// same cells, the same empty tiles in any order, and for colored puzzles the
+// same colors and goal. Unlike comparing String results, colored puzzles that
+// differ only in which of two same-colored tiles is where are not equal. The
+// zero Puzzle is only equal to itself.
+func (p Puzzle) Equal(q Puzzle) bool {
+	if p.Rows() != q.Rows() {
+		return false
+	}
+	if p.Rows() == 0 {
+		return true
+	}
+	return p.Cols() == q.Cols() && slices.Equal(p.Values(), q.Values()) &&
+		slices.Equal(p.sortedEmptyValues(), q.sortedEmptyValues()) &&
+		slices.Equal(p.colors, q.colors) &&
+		slices.Equal(p.wildcardCells(), q.wildcardCells())
+}
+
+// Hash returns a 64-bit hash of p that is the same for equal puzzles and does
+// not change between runs or program versions, so it can be stored. It is the
+// 64-bit FNV-1a hash of the binary form.
+func (p Puzzle) Hash() uint64 {
+	b, _ := p.MarshalBinary()
+	h := fnv.New64a()
+	h.Write(b)
+	return h.Sum64()
+}
+
+// MarshalText encodes m as its name, such as "North".
+func (m Move) MarshalText() ([]byte, error) {
+	if _, ok := moveStrings[m]; !ok {
+		return nil, &InvalidMoveError{fmt.Sprintf("invalid move %d", int(m))}
+	}
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText decodes a single move in the TileMoves convention, spelled
+// any way ParseMoves accepts.
+func (m *Move) UnmarshalText(text []byte) error {
+	moves, err := ParseMoves(string(text), TileMoves)
+	if err != nil {
+		return err
+	}
+	if len(moves) != 1 {
+		return &InvalidMoveError{fmt.Sprintf("want a single move; got %q", text)}
+	}
+	*m = moves[0]
+	return nil
+}
+
+// MarshalBinary encodes m as a single byte.
+func (m Move) MarshalBinary() ([]byte, error) {
+	if _, ok := moveStrings[m]; !ok {
+		return nil, &InvalidMoveError{fmt.Sprintf("invalid move %d", int(m))}
+	}
+	return []byte{byte(m)}, nil
+}
+
+// UnmarshalBinary decodes a move encoded by MarshalBinary.
+func (m *Move) UnmarshalBinary(data []byte) error {
+	if len(data) != 1 {
+		return &InvalidMoveError{fmt.Sprintf("move data must be 1 byte; got %d", len(data))}
+	}
+	if _, ok := moveStrings[Move(data[0])]; !ok {
+		return &InvalidMoveError{fmt.Sprintf("invalid move %d", data[0])}
+	}
+	*m = Move(data[0])
+	return nil
+}
+
+// sortedEmptyValues returns the values of the empty tiles in increasing order,
+// so that puzzles given the same empty tiles in a different order encode the
+// same.
+func (p Puzzle) sortedEmptyValues() []int {
+	values := p.EmptyValues()
+	slices.Sort(values)
+	return values
+}
+
+// wildcardCells returns the cells whose goal accepts any tile, in row-major
+// order.
+func (p Puzzle) wildcardCells() []int {
+	var cells []int
+	for cell, wild := range p.wildcards {
+		if wild {
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
+
+// buildPuzzle makes a puzzle from its decoded parts, checking that they fit
+// together. colors and wildcards are empty for puzzles without colors.
+func buildPuzzle(grid [][]int, empty, colors, wildcards []int) (*Puzzle, error) {
+	p, err := NewPuzzleWithHoles(grid, empty)
+	if err != nil {
+		return nil, err
+	}
+	if len(colors) == 0 {
+		if len(wildcards) > 0 {
+			return nil, &InvalidPuzzleError{"puzzles with wildcards must have colors"}
+		}
+		return p, nil
+	}
+
+	values := p.Values()
+	if len(colors) != len(values) {
+		return nil, &InvalidPuzzleError{fmt.Sprintf("puzzle has %d cells but %d colors", len(values), len(colors))}
+	}
+	for cell, color := range colors {
+		if (color == Blocked) != (values[cell] == Blocked) || color == Wildcard {
+			return nil, &InvalidPuzzleError{fmt.Sprintf("invalid color %d for cell %d", color, cell)}
+		}
+	}
+	p.colors = colors
+
+	if len(wildcards) > 0 {
+		p.wildcards = make([]bool, len(values))
+		for _, cell := range wildcards {
+			if cell < 0 || cell >= len(values) || values[cell] == Blocked || p.wildcards[cell] {
+				return nil, &InvalidPuzzleError{fmt.Sprintf("invalid wildcard cell %d", cell)}
+			}
+			p.wildcards[cell] = true
+		}
+	}
+	return p, nil
+}
+
+// formatCells writes values as rows of cols values separated by spaces, with
+// the rows separated by slashes and "#" for Blocked.
+func formatCells(values []int, cols int) string {
+	rows := make([]string, 0, len(values)/cols)
+	for row := range slices.Chunk(values, cols) {
+		cells := make([]string, len(row))
+		for i, val := range row {
+			cells[i] = "#"
+			if val != Blocked {
+				cells[i] = strconv.Itoa(val)
+			}
+		}
+		rows = append(rows, strings.Join(cells, " "))
+	}
+	return strings.Join(rows, "/")
+}
+
+// parseCells parses values written by formatCells.
+func parseCells(s string) ([][]int, error) {
+	var grid [][]int
+	for line := range strings.SplitSeq(s, "/") {
+		row, err := ReadGrid(strings.NewReader(line))
+		if err != nil {
+			return nil, err
+		}
+		if len(row) != 1 {
+			return nil, &InvalidPuzzleError{fmt.Sprintf("invalid puzzle row %q", line)}
+		}
+		grid = append(grid, row[0])
+	}
+	return grid, nil
+}
+
+func joinInts(values []int) string {
+	s := make([]string, len(values))
+	for i, val := range values {
+		s[i] = strconv.Itoa(val)
+	}
+	return strings.Join(s, ",")
+}
+
+func splitInts(s string) ([]int, error) {
+	var values []int
+	for field := range strings.SplitSeq(s, ",") {
+		val, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, &InvalidPuzzleError{fmt.Sprintf("invalid value %q", field)}
+		}
+		values = append(values, val)
+	}
+	return values, nil
+}
+
+func appendVarints(b []byte, values []int) []byte {
+	b = binary.AppendUvarint(b, uint64(len(values)))
+	for _, val := range values {
+		b = binary.AppendVarint(b, int64(val))
+	}
+	return b
+}
+
+// varintDecoder reads the numbers written by MarshalBinary, remembering the
+// first error so that it only needs checking at the end.
+type varintDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *varintDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	val, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = &InvalidPuzzleError{"truncated or invalid puzzle data"}
+		return 0
+	}
+	d.data = d.data[n:]
+	return val
+}
+
+func (d *varintDecoder) varints() []int {
+	n := d.uvarint()
+	if d.err == nil && n > uint64(len(d.data)) {
+		d.err = &InvalidPuzzleError{"truncated or invalid puzzle data"}
+	}
+	if d.err != nil {
+		return nil
+	}
+	values := make([]int, n)
+	for i := range values {
+		val, m := binary.Varint(d.data)
+		if m <= 0 {
+			d.err = &InvalidPuzzleError{"truncated or invalid puzzle data"}
+			return nil
+		}
+		values[i] = int(val)
+		d.data = d.data[m:]
+	}
+	return values
+}
@@ -0,0 +1,276 @@
+package slide_puzzle
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// marshalTestPuzzles returns puzzles covering every part of the encoding.
+func marshalTestPuzzles(t *testing.T) map[string]*Puzzle {
+	t.Helper()
+	plain, err := NewPuzzle([][]int{{1, 2, 3}, {4, 0, 5}, {6, 7, 8}}, 0)
+	if err != nil {
+		t.Fatalf("NewPuzzle() error: %v", err)
+	}
+	blocked, err := NewPuzzle([][]int{{4, 1, 2}, {Blocked, 0, 5}}, 0)
+	if err != nil {
+		t.Fatalf("NewPuzzle() error: %v", err)
+	}
+	holes, err := NewPuzzleWithHoles([][]int{{1, 2, 3}, {4, 0, 5}}, []int{0, 5})
+	if err != nil {
+		t.Fatalf("NewPuzzleWithHoles() error: %v", err)
+	}
+	colored, err := NewColoredPuzzle([][]int{{1, 0, 1}, {2, 1, 2}}, [][]int{{0, 1, 1}, {2, 2, 1}}, 0)
+	if err != nil {
+		t.Fatalf("NewColoredPuzzle() error: %v", err)
+	}
+	partial, err := plain.PartialGoal(1, 2)
+	if err != nil {
+		t.Fatalf("PartialGoal() error: %v", err)
+	}
+	return map[string]*Puzzle{
+		"plain": plain, "blocked": blocked, "holes": holes, "colored": colored, "partial goal": partial,
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	t.Run("format", func(t *testing.T) {
+		p, err := NewPuzzle([][]int{{4, 1, 2}, {Blocked, 0, 5}}, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		got, err := p.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText() error: %v", err)
+		}
+		if want := "grid=4 1 2/# 0 5; empty=0"; string(got) != want {
+			t.Errorf("MarshalText() = %q, want %q", got, want)
+		}
+	})
+
+	for name, p := range marshalTestPuzzles(t) {
+		t.Run(name+" round trip", func(t *testing.T) {
+			text, err := p.MarshalText()
+			if err != nil {
+				t.Fatalf("MarshalText() error: %v", err)
+			}
+			var got Puzzle
+			if err := got.UnmarshalText(text); err != nil {
+				t.Fatalf("UnmarshalText(%q) error: %v", text, err)
+			}
+			if !got.Equal(*p) {
+				t.Errorf("UnmarshalText(%q) = %v, want %v", text, got, p)
+			}
+		})
+	}
+
+	t.Run("zero puzzle round trip", func(t *testing.T) {
+		text, err := Puzzle{}.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText() error: %v", err)
+		}
+		if len(text) != 0 {
+			t.Errorf("MarshalText() = %q, want empty text", text)
+		}
+		got := *marshalTestPuzzles(t)["plain"]
+		if err := got.UnmarshalText(text); err != nil {
+			t.Fatalf("UnmarshalText(%q) error: %v", text, err)
+		}
+		if !got.Equal(Puzzle{}) {
+			t.Errorf("UnmarshalText(%q) = %v, want the zero puzzle", text, got)
+		}
+	})
+
+	for name, text := range map[string]string{
+		"missing empty":     "grid=1 0/2 3",
+		"unknown field":     "grid=1 0/2 3; empty=0; size=2",
+		"duplicate field":   "grid=1 0/2 3; empty=0; empty=1",
+		"invalid value":     "grid=1 x/2 3; empty=0",
+		"invalid grid":      "grid=1 1/2 3; empty=0",
+		"wrong colors":      "grid=1 0/2 3; empty=0; colors=1 1 1",
+		"wildcards alone":   "grid=1 0/2 3; empty=0; wildcards=1",
+		"wildcard off grid": "grid=1 0/2 3; empty=0; colors=0 1/1 1; wildcards=4",
+	} {
+		t.Run(name, func(t *testing.T) {
+			var p Puzzle
+			err := p.UnmarshalText([]byte(text))
+			var invalidErr *InvalidPuzzleError
+			if !errors.As(err, &invalidErr) {
+				t.Errorf("UnmarshalText(%q) error type = %T, want *InvalidPuzzleError", text, err)
+			}
+		})
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	for name, p := range marshalTestPuzzles(t) {
+		t.Run(name+" round trip", func(t *testing.T) {
+			data, err := p.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary() error: %v", err)
+			}
+			var got Puzzle
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary() error: %v", err)
+			}
+			if !got.Equal(*p) {
+				t.Errorf("UnmarshalBinary() = %v, want %v", got, p)
+			}
+
+			// Every truncation is rejected rather than misread.
+			for n := range len(data) {
+				if err := got.UnmarshalBinary(data[:n]); err == nil {
+					t.Errorf("UnmarshalBinary() of first %d of %d bytes succeeded", n, len(data))
+				}
+			}
+		})
+	}
+
+	t.Run("zero puzzle round trip", func(t *testing.T) {
+		data, err := Puzzle{}.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary() error: %v", err)
+		}
+		got := *marshalTestPuzzles(t)["plain"]
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary() error: %v", err)
+		}
+		if !got.Equal(Puzzle{}) {
+			t.Errorf("UnmarshalBinary() = %v, want the zero puzzle", got)
+		}
+	})
+
+	t.Run("wrong version returns error", func(t *testing.T) {
+		var p Puzzle
+		err := p.UnmarshalBinary([]byte{99, 1, 1, 1, 0, 1, 0, 0, 0})
+		var invalidErr *InvalidPuzzleError
+		if !errors.As(err, &invalidErr) {
+			t.Errorf("UnmarshalBinary() error type = %T, want *InvalidPuzzleError", err)
+		}
+	})
+}
+
+func TestEqualAndHash(t *testing.T) {
+	puzzles := marshalTestPuzzles(t)
+	for name, p := range puzzles {
+		for otherName, q := range puzzles {
+			if got, want := p.Equal(*q), name == otherName; got != want {
+				t.Errorf("%s.Equal(%s) = %v, want %v", name, otherName, got, want)
+			}
+			if name != otherName && p.Hash() == q.Hash() {
+				t.Errorf("%s and %s have the same Hash() %#x", name, otherName, p.Hash())
+			}
+		}
+	}
+
+	t.Run("same shape matters", func(t *testing.T) {
+		wide, err := NewPuzzle([][]int{{0, 1, 2, 3, 4, 5}}, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		tall, err := NewPuzzle([][]int{{0, 1, 2}, {3, 4, 5}}, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		if wide.Equal(*tall) {
+			t.Errorf("Equal() = true for %v and %v", wide, tall)
+		}
+	})
+
+	t.Run("order of empty tiles does not matter", func(t *testing.T) {
+		grid := [][]int{{1, 2, 3}, {4, 0, 5}}
+		p, err := NewPuzzleWithHoles(grid, []int{0, 5})
+		if err != nil {
+			t.Fatalf("NewPuzzleWithHoles() error: %v", err)
+		}
+		q, err := NewPuzzleWithHoles(grid, []int{5, 0})
+		if err != nil {
+			t.Fatalf("NewPuzzleWithHoles() error: %v", err)
+		}
+		if !p.Equal(*q) {
+			t.Errorf("Equal() = false for %v and %v", p, q)
+		}
+		if p.Hash() != q.Hash() {
+			t.Errorf("Hash() = %#x and %#x for %v and %v", p.Hash(), q.Hash(), p, q)
+		}
+	})
+
+	t.Run("zero puzzle", func(t *testing.T) {
+		var zero Puzzle
+		if !zero.Equal(Puzzle{}) {
+			t.Errorf("Equal() = false for two zero puzzles")
+		}
+		for name, p := range puzzles {
+			if zero.Equal(*p) || p.Equal(zero) {
+				t.Errorf("Equal() = true for the zero puzzle and %s", name)
+			}
+		}
+		if zero.Hash() == puzzles["plain"].Hash() {
+			t.Errorf("zero puzzle has the same Hash() as %v", puzzles["plain"])
+		}
+	})
+
+	t.Run("hash is stable", func(t *testing.T) {
+		// The hash is meant to be stored, so it must never change.
+		p, err := Goal(2, 2, 0)
+		if err != nil {
+			t.Fatalf("Goal() error: %v", err)
+		}
+		if got, want := p.Hash(), uint64(0x934cfed6fca593a5); got != want {
+			t.Errorf("Hash() = %#x, want %#x", got, want)
+		}
+	})
+}
+
+func TestMarshalMove(t *testing.T) {
+	t.Run("JSON round trip", func(t *testing.T) {
+		moves := []Move{North, East, South, West}
+		data, err := json.Marshal(moves)
+		if err != nil {
+			t.Fatalf("json.Marshal() error: %v", err)
+		}
+		if want := `["North","East","South","West"]`; string(data) != want {
+			t.Errorf("json.Marshal() = %s, want %s", data, want)
+		}
+		var got []Move
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("json.Unmarshal() error: %v", err)
+		}
+		if diff := cmp.Diff(moves, got); diff != "" {
+			t.Errorf("json.Unmarshal() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("binary round trip", func(t *testing.T) {
+		for _, m := range allMoves {
+			data, err := m.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary() error: %v", err)
+			}
+			var got Move
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary() error: %v", err)
+			}
+			if got != m {
+				t.Errorf("UnmarshalBinary() = %v, want %v", got, m)
+			}
+		}
+	})
+
+	t.Run("invalid moves return error", func(t *testing.T) {
+		var invalidErr *InvalidMoveError
+		if _, err := Move(7).MarshalText(); !errors.As(err, &invalidErr) {
+			t.Errorf("MarshalText() error type = %T, want *InvalidMoveError", err)
+		}
+		var m Move
+		if err := m.UnmarshalText([]byte("NE")); !errors.As(err, &invalidErr) {
+			t.Errorf("UnmarshalText() error type = %T, want *InvalidMoveError", err)
+		}
+		if err := m.UnmarshalBinary([]byte{7}); !errors.As(err, &invalidErr) {
+			t.Errorf("UnmarshalBinary() error type = %T, want *InvalidMoveError", err)
+		}
+	})
+}
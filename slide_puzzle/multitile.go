@@ -0,0 +1,98 @@
+package slide_puzzle
+
+import "fmt"
+
+// MultiMove slides Count tiles in a line one step in direction Dir at once,
+// the nearest of them into the empty space. Under the multi-tile metric used
+// in many competitions, it counts as a single move.
+type MultiMove struct {
+	Dir   Move
+	Count int
+}
+
+// String returns the direction, followed by the count if more than one tile
+// moves, e.g. "North" or "North x3".
+func (m MultiMove) String() string {
+	if m.Count == 1 {
+		return m.Dir.String()
+	}
+	return fmt.Sprintf("%s x%d", m.Dir, m.Count)
+}
+
+// Expand returns the equivalent sequence of single-tile moves.
+func (m MultiMove) Expand() []Move {
+	moves := make([]Move, m.Count)
+	for i := range moves {
+		moves[i] = m.Dir
+	}
+	return moves
+}
+
+// ExpandMultiMoves returns the single-tile moves equivalent to moves.
+func ExpandMultiMoves(moves []MultiMove) []Move {
+	var expanded []Move
+	for _, m := range moves {
+		expanded = append(expanded, m.Expand()...)
+	}
+	return expanded
+}
+
+// SolveMultiTile finds a solution with the fewest moves when each move may
+// slide up to k tiles in the same row or column as the empty space toward it.
+// If k is not positive, any number of tiles may slide at once. Like Solve, it
+// uses breadth-first search, so it is only practical for small boards.
+func (p Puzzle) SolveMultiTile(k int) ([]MultiMove, error) {
+	if err := p.requireSingleEmpty(); err != nil {
+		return nil, err
+	}
+	if err := p.requireDistinct(); err != nil {
+		return nil, err
+	}
+	if !p.isSolvable() {
+		return nil, UnsolvablePuzzleError{}
+	}
+	if p.isSolved() {
+		return []MultiMove{}, nil
+	}
+
+	type state struct {
+		board *flatPuzzle
+		moves []MultiMove
+	}
+
+	start := newFlatPuzzle(p)
+	queue := []state{{board: start, moves: []MultiMove{}}}
+	visited := map[string]bool{start.key(): true}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, dir := range allMoves {
+			board := current.board.clone()
+			for count := 1; k <= 0 || count <= k; count++ {
+				src := board.source(dir)
+				if src < 0 {
+					break
+				}
+				board.apply(src)
+
+				// Copy current moves and append this move.
+				newMoves := make([]MultiMove, len(current.moves)+1)
+				copy(newMoves, current.moves)
+				newMoves[len(current.moves)] = MultiMove{Dir: dir, Count: count}
+				if board.h == 0 {
+					return newMoves, nil
+				}
+
+				key := board.key()
+				if !visited[key] {
+					visited[key] = true
+					queue = append(queue, state{board: board.clone(), moves: newMoves})
+				}
+			}
+		}
+	}
+
+	return nil, UnsolvablePuzzleError{}
+}
@@ -0,0 +1,93 @@
+package slide_puzzle
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSolveMultiTile(t *testing.T) {
+	t.Run("whole row slides in one move", func(t *testing.T) {
+		p, err := NewPuzzle([][]int{{1, 2, 3, 0}}, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		got, err := p.SolveMultiTile(0)
+		if err != nil {
+			t.Fatalf("SolveMultiTile() error: %v", err)
+		}
+		want := []MultiMove{{Dir: East, Count: 3}}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("SolveMultiTile() mismatch (-want +got):\n%s", diff)
+		}
+
+		got, err = p.SolveMultiTile(2)
+		if err != nil {
+			t.Fatalf("SolveMultiTile() error: %v", err)
+		}
+		if len(got) != 2 {
+			t.Errorf("SolveMultiTile(2) = %v, want 2 moves", got)
+		}
+	})
+
+	rng := rand.New(rand.NewPCG(4, 2))
+	for range 10 {
+		p, err := Scramble(2, 4, 0, 40, rng)
+		if err != nil {
+			t.Fatalf("Scramble() error: %v", err)
+		}
+		single, err := p.Solve()
+		if err != nil {
+			t.Fatalf("Solve() error: %v", err)
+		}
+
+		t.Run("one tile at a time matches Solve", func(t *testing.T) {
+			got, err := p.SolveMultiTile(1)
+			if err != nil {
+				t.Fatalf("SolveMultiTile() error: %v", err)
+			}
+			if len(got) != len(single) {
+				t.Errorf("SolveMultiTile(1) took %d moves, want %d", len(got), len(single))
+			}
+			assertSolves(t, *p, ExpandMultiMoves(got))
+		})
+
+		t.Run("sliding lines needs no more moves", func(t *testing.T) {
+			got, err := p.SolveMultiTile(0)
+			if err != nil {
+				t.Fatalf("SolveMultiTile() error: %v", err)
+			}
+			if len(got) > len(single) {
+				t.Errorf("SolveMultiTile(0) took %d moves, more than %d", len(got), len(single))
+			}
+			assertSolves(t, *p, ExpandMultiMoves(got))
+		})
+	}
+
+	t.Run("unsolvable puzzle returns error", func(t *testing.T) {
+		p, err := NewPuzzle([][]int{{0, 2, 1}, {3, 4, 5}}, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		_, err = p.SolveMultiTile(0)
+		if !errors.As(err, &UnsolvablePuzzleError{}) {
+			t.Errorf("SolveMultiTile() error = %v, want UnsolvablePuzzleError", err)
+		}
+	})
+}
+
+func TestMultiMoveString(t *testing.T) {
+	for _, tt := range []struct {
+		move MultiMove
+		want string
+	}{
+		{MultiMove{Dir: North, Count: 1}, "North"},
+		{MultiMove{Dir: West, Count: 3}, "West x3"},
+	} {
+		if got := tt.move.String(); got != tt.want {
+			t.Errorf("%#v.String() = %q, want %q", tt.move, got, tt.want)
+		}
+	}
+}
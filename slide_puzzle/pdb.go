@@ -0,0 +1,245 @@
+package slide_puzzle
+
+import (
+	"fmt"
+	"slices"
+)
+
+// maxPatternEntries limits the size of a pattern database, at one byte per
+// entry.
+const maxPatternEntries = 1 << 28
+
+// patternUnvisited marks pattern database entries not yet reached.
+const patternUnvisited = 0xff
+
+// PatternDatabase holds the exact number of moves needed to bring a subset of
+// the tiles, the pattern, to their goal positions, for every placement of
+// those tiles and the empty tile. The other tiles are ignored, so this is a
+// lower bound on the length of a solution, and usually a much better one than
+// the Manhattan distance.
+//
+// For square boards whose empty tile belongs on the main diagonal, reflecting
+// a puzzle about that diagonal gives a puzzle with the same solution length.
+// The database is then also looked up with the reflected puzzle, which covers
+// the reflection of the pattern for free, and the larger value is used.
+type PatternDatabase struct {
+	rows, cols int
+	emptyValue int
+	tiles      []int
+	// slot maps each tile value to its index in a placement: 0 to
+	// len(tiles)-1 for pattern tiles, len(tiles) for the empty tile, and -1
+	// for tiles not in the pattern.
+	slot      []int
+	distances []uint8
+	symmetric bool
+}
+
+// NewPatternDatabase computes the pattern database for the given tiles by
+// breadth-first search backwards from the goal.
+func NewPatternDatabase(rows, cols, emptyTileValue int, tiles []int) (*PatternDatabase, error) {
+	goal, err := Goal(rows, cols, emptyTileValue)
+	if err != nil {
+		return nil, err
+	}
+	n := rows * cols
+
+	slot := make([]int, n)
+	for i := range slot {
+		slot[i] = -1
+	}
+	for i, t := range tiles {
+		if t < 0 || t >= n || t == emptyTileValue || slot[t] >= 0 {
+			return nil, &InvalidPuzzleError{fmt.Sprintf("invalid pattern tile %d for a %dx%d board with empty tile %d", t, rows, cols, emptyTileValue)}
+		}
+		slot[t] = i
+	}
+	slot[emptyTileValue] = len(tiles)
+
+	entries := 1
+	for i := range len(tiles) + 1 {
+		entries *= n - i
+		if entries > maxPatternEntries {
+			return nil, &InvalidPuzzleError{fmt.Sprintf("pattern of %d tiles is too large for a %dx%d board", len(tiles), rows, cols)}
+		}
+	}
+
+	db := &PatternDatabase{
+		rows:       rows,
+		cols:       cols,
+		emptyValue: emptyTileValue,
+		tiles:      slices.Clone(tiles),
+		slot:       slot,
+		distances:  make([]uint8, entries),
+		symmetric:  goal.hasDiagonalSymmetry(),
+	}
+	for i := range db.distances {
+		db.distances[i] = patternUnvisited
+	}
+	db.build(goal.Values())
+	return db, nil
+}
+
+// build fills in the distances by breadth-first search from the goal cells.
+func (db *PatternDatabase) build(goal []int) {
+	n := db.rows * db.cols
+	placement := make([]int, len(db.tiles)+1)
+	db.place(placement, goal, false)
+	empty := len(db.tiles)
+
+	// occupant[i] is the slot of the tile at cell i, or -1.
+	occupant := make([]int, n)
+	root := rankPlacement(placement, n)
+	db.distances[root] = 0
+	queue := []uint32{uint32(root)}
+	for len(queue) > 0 {
+		rank := int(queue[0])
+		queue = queue[1:]
+		distance := db.distances[rank]
+
+		unrankPlacement(rank, n, placement)
+		for i := range occupant {
+			occupant[i] = -1
+		}
+		for s, i := range placement {
+			occupant[i] = s
+		}
+
+		at := placement[empty]
+		row, col := at/db.cols, at%db.cols
+		for _, next := range []struct {
+			ok  bool
+			src int
+		}{
+			{row > 0, at - db.cols},
+			{row < db.rows-1, at + db.cols},
+			{col > 0, at - 1},
+			{col < db.cols-1, at + 1},
+		} {
+			if !next.ok {
+				continue
+			}
+			placement[empty] = next.src
+			if s := occupant[next.src]; s >= 0 {
+				placement[s] = at
+			}
+			if r := rankPlacement(placement, n); db.distances[r] == patternUnvisited {
+				db.distances[r] = min(distance+1, patternUnvisited-1)
+				queue = append(queue, uint32(r))
+			}
+			placement[empty] = at
+			if s := occupant[next.src]; s >= 0 {
+				placement[s] = next.src
+			}
+		}
+	}
+}
+
+// place fills placement with the cells of the pattern tiles and the empty
+// tile in cells, or in the reflection of cells about the main diagonal.
+func (db *PatternDatabase) place(placement, cells []int, reflect bool) {
+	for i, val := range cells {
+		if reflect {
+			c := Transpose.coord(coord{row: i / db.cols, col: i % db.cols}, db.rows, db.cols)
+			i = c.index(db.cols)
+			val = Transpose.value(val, db.rows, db.cols)
+		}
+		if s := db.slot[val]; s >= 0 {
+			placement[s] = i
+		}
+	}
+}
+
+// lookup returns the pattern distance for a board given in row-major order.
+func (db *PatternDatabase) lookup(cells []int) int {
+	var buf [16]int
+	placement := buf[:0]
+	if len(db.tiles)+1 > len(buf) {
+		placement = make([]int, 0, len(db.tiles)+1)
+	}
+	placement = placement[:len(db.tiles)+1]
+
+	n := db.rows * db.cols
+	db.place(placement, cells, false)
+	h := int(db.distances[rankPlacement(placement, n)])
+	if db.symmetric {
+		db.place(placement, cells, true)
+		h = max(h, int(db.distances[rankPlacement(placement, n)]))
+	}
+	return h
+}
+
+// Lookup returns a lower bound on the number of moves needed to solve p, which
+// must have the same size and empty tile as the database.
+func (db *PatternDatabase) Lookup(p Puzzle) (int, error) {
+	if err := db.check(p); err != nil {
+		return 0, err
+	}
+	return db.lookup(p.Values()), nil
+}
+
+// Symmetric reports whether lookups also use the puzzle reflected about the
+// main diagonal.
+func (db *PatternDatabase) Symmetric() bool {
+	return db.symmetric
+}
+
+func (db *PatternDatabase) check(p Puzzle) error {
+	if err := p.requireSingleEmpty(); err != nil {
+		return err
+	}
+	if err := p.requireDistinct(); err != nil {
+		return err
+	}
+	if err := p.requireNoBlocked(); err != nil {
+		return err
+	}
+	if len(p.grid) != db.rows || len(p.grid[0]) != db.cols || p.emptyTile.value != db.emptyValue {
+		return &InvalidPuzzleError{fmt.Sprintf(
+			"pattern database is for %dx%d puzzles with empty tile %d; got %dx%d with empty tile %d",
+			db.rows, db.cols, db.emptyValue, len(p.grid), len(p.grid[0]), p.emptyTile.value,
+		)}
+	}
+	return nil
+}
+
+// rankPlacement returns the index of a placement of distinct cells out of n,
+// counting placements in lexicographic order.
+func rankPlacement(placement []int, n int) int {
+	rank := 0
+	for i, cell := range placement {
+		smaller := 0
+		for _, other := range placement[:i] {
+			if other < cell {
+				smaller++
+			}
+		}
+		rank = rank*(n-i) + cell - smaller
+	}
+	return rank
+}
+
+// unrankPlacement fills placement with the placement of distinct cells out of
+// n with the given rank.
+func unrankPlacement(rank, n int, placement []int) {
+	for i := len(placement) - 1; i >= 0; i-- {
+		placement[i] = rank % (n - i)
+		rank /= n - i
+	}
+	// Each digit counts the free cells before the chosen one.
+	for i, free := range placement {
+		cell := free
+		for {
+			used := 0
+			for _, other := range placement[:i] {
+				if other <= cell {
+					used++
+				}
+			}
+			if cell-used == free {
+				break
+			}
+			cell = free + used
+		}
+		placement[i] = cell
+	}
+}
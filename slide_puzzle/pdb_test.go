@@ -0,0 +1,117 @@
+package slide_puzzle
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestRankPlacement(t *testing.T) {
+	const n = 6
+	placement := make([]int, 3)
+	seen := map[[3]int]bool{}
+	for rank := range n * (n - 1) * (n - 2) {
+		unrankPlacement(rank, n, placement)
+		key := [3]int(placement)
+		if seen[key] {
+			t.Fatalf("unrankPlacement(%d) = %v, which was already produced", rank, placement)
+		}
+		seen[key] = true
+		if got := rankPlacement(placement, n); got != rank {
+			t.Fatalf("rankPlacement(%v) = %d, want %d", placement, got, rank)
+		}
+	}
+}
+
+func TestPatternDatabase(t *testing.T) {
+	db, err := NewPatternDatabase(3, 3, 0, []int{1, 2, 3, 4})
+	if err != nil {
+		t.Fatalf("NewPatternDatabase() error: %v", err)
+	}
+	if !db.Symmetric() {
+		t.Errorf("Symmetric() = false for a 3x3 board with the empty tile on the diagonal")
+	}
+	plain := *db
+	plain.symmetric = false
+
+	t.Run("lookups are admissible and use symmetry", func(t *testing.T) {
+		rng := rand.New(rand.NewPCG(5, 6))
+		for range 20 {
+			p, err := Scramble(3, 3, 0, 60, rng)
+			if err != nil {
+				t.Fatalf("Scramble() error: %v", err)
+			}
+			moves, err := p.SolveIDAStar(1)
+			if err != nil {
+				t.Fatalf("SolveIDAStar() error: %v", err)
+			}
+			h, err := db.Lookup(*p)
+			if err != nil {
+				t.Fatalf("Lookup() error: %v", err)
+			}
+			if h > len(moves) {
+				t.Errorf("Lookup(%v) = %d, more than optimal %d", p, h, len(moves))
+			}
+
+			plainH, _ := plain.Lookup(*p)
+			reflectedH, _ := plain.Lookup(p.Transform(Transpose))
+			if want := max(plainH, reflectedH); h != want {
+				t.Errorf("Lookup(%v) = %d, want max(%d, %d)", p, h, plainH, reflectedH)
+			}
+		}
+	})
+
+	t.Run("solutions are optimal", func(t *testing.T) {
+		rng := rand.New(rand.NewPCG(7, 8))
+		for range 10 {
+			p, err := Scramble(3, 3, 0, 60, rng)
+			if err != nil {
+				t.Fatalf("Scramble() error: %v", err)
+			}
+			want, err := p.SolveIDAStar(2)
+			if err != nil {
+				t.Fatalf("SolveIDAStar() error: %v", err)
+			}
+			got, err := p.SolveIDAStarPDB(db, 2)
+			if err != nil {
+				t.Fatalf("SolveIDAStarPDB() error: %v", err)
+			}
+			assertSolves(t, *p, got)
+			if len(got) != len(want) {
+				t.Errorf("SolveIDAStarPDB(%v) took %d moves, want %d", p, len(got), len(want))
+			}
+		}
+	})
+
+	t.Run("no symmetry off the diagonal", func(t *testing.T) {
+		db, err := NewPatternDatabase(3, 3, 1, []int{0, 2})
+		if err != nil {
+			t.Fatalf("NewPatternDatabase() error: %v", err)
+		}
+		if db.Symmetric() {
+			t.Errorf("Symmetric() = true for empty tile 1 on a 3x3 board")
+		}
+	})
+
+	t.Run("invalid tiles return error", func(t *testing.T) {
+		for _, tiles := range [][]int{{0}, {9}, {1, 1}} {
+			_, err := NewPatternDatabase(3, 3, 0, tiles)
+			var invalidErr *InvalidPuzzleError
+			if !errors.As(err, &invalidErr) {
+				t.Errorf("NewPatternDatabase(%v) error type = %T, want *InvalidPuzzleError", tiles, err)
+			}
+		}
+	})
+
+	t.Run("wrong size returns error", func(t *testing.T) {
+		p, err := Goal(2, 3, 0)
+		if err != nil {
+			t.Fatalf("Goal() error: %v", err)
+		}
+		_, err = p.SolveIDAStarPDB(db, 1)
+		var invalidErr *InvalidPuzzleError
+		if !errors.As(err, &invalidErr) {
+			t.Errorf("SolveIDAStarPDB() error type = %T, want *InvalidPuzzleError", err)
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package slide_puzzle
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg" // Register the JPEG decoder for LoadPicture.
+	_ "image/png"  // Register the PNG decoder for LoadPicture.
+	"io"
+)
+
+// Picture is an image sliced into a grid of tiles for picture puzzles. The
+// piece at row r and column c of the image is used for tile value r*cols+c, so
+// the goal state reassembles the original image.
+type Picture struct {
+	rows, cols int
+	tileSize   image.Point
+	// pieces is indexed by tile value.
+	pieces []*image.RGBA
+}
+
+// ImageSizeError is returned when an image is too small to slice into the
+// requested number of tiles.
+type ImageSizeError struct {
+	msg string
+}
+
+func (e ImageSizeError) Error() string {
+	return e.msg
+}
+
+// LoadPicture decodes a PNG or JPEG image and slices it into rows x cols tiles.
+func LoadPicture(r io.Reader, rows, cols int) (*Picture, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	return NewPicture(img, rows, cols)
+}
+
+// NewPicture slices img into rows x cols tiles of equal size. If the image
+// dimensions are not multiples of the grid dimensions, the excess pixels on the
+// right and bottom edges are discarded.
+func NewPicture(img image.Image, rows, cols int) (*Picture, error) {
+	if rows <= 0 || cols <= 0 {
+		return nil, &InvalidPuzzleError{fmt.Sprintf("puzzle dimensions must be positive; got %dx%d", rows, cols)}
+	}
+	bounds := img.Bounds()
+	tileSize := image.Pt(bounds.Dx()/cols, bounds.Dy()/rows)
+	if tileSize.X == 0 || tileSize.Y == 0 {
+		return nil, &ImageSizeError{fmt.Sprintf(
+			"image of size %dx%d is too small for %dx%d tiles", bounds.Dx(), bounds.Dy(), rows, cols,
+		)}
+	}
+
+	pic := &Picture{rows: rows, cols: cols, tileSize: tileSize, pieces: make([]*image.RGBA, rows*cols)}
+	for row := range rows {
+		for col := range cols {
+			src := bounds.Min.Add(image.Pt(col*tileSize.X, row*tileSize.Y))
+			piece := image.NewRGBA(image.Rectangle{Max: tileSize})
+			draw.Draw(piece, piece.Rect, img, src, draw.Src)
+			pic.pieces[row*cols+col] = piece
+		}
+	}
+	return pic, nil
+}
+
+// Render reassembles the picture according to the tile positions in p. The
+// empty tile is drawn as a blank space.
+func (pic *Picture) Render(p Puzzle) (*image.RGBA, error) {
+	if err := pic.checkSize(p); err != nil {
+		return nil, err
+	}
+	return pic.render(p, nil), nil
+}
+
+// RenderFrames reassembles the picture for the starting puzzle and for the
+// board after each move in the given solution. In every frame but the first,
+// the tile that just moved is outlined.
+func (pic *Picture) RenderFrames(p Puzzle, moves []Move) ([]*image.RGBA, error) {
+	if err := pic.checkSize(p); err != nil {
+		return nil, err
+	}
+
+	frames := make([]*image.RGBA, 0, len(moves)+1)
+	frames = append(frames, pic.render(p, nil))
+
+	current := p
+	for _, move := range moves {
+		moved := current.emptyTile.coord
+		next, err := current.makeMove(move)
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, pic.render(next, &moved))
+		current = next
+	}
+	return frames, nil
+}
+
+func (pic *Picture) checkSize(p Puzzle) error {
+	if len(p.grid) != pic.rows || len(p.grid[0]) != pic.cols {
+		return &InvalidPuzzleError{fmt.Sprintf(
+			"puzzle is %dx%d but picture is sliced into %dx%d tiles",
+			len(p.grid), len(p.grid[0]), pic.rows, pic.cols,
+		)}
+	}
+	return nil
+}
+
+func (pic *Picture) render(p Puzzle, highlight *coord) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, pic.cols*pic.tileSize.X, pic.rows*pic.tileSize.Y))
+	empty := image.NewUniform(renderPalette[paletteEmpty])
+	blocked := image.NewUniform(renderPalette[paletteBackground])
+	outline := image.NewUniform(renderPalette[paletteHighlight])
+	border := max(1, min(pic.tileSize.X, pic.tileSize.Y)/16)
+
+	for row := range p.grid {
+		for col := range p.grid[row] {
+			cell := image.Rectangle{Max: pic.tileSize}.Add(image.Pt(col*pic.tileSize.X, row*pic.tileSize.Y))
+			here := coord{row: row, col: col}
+			if p.grid[row][col] == Blocked {
+				draw.Draw(img, cell, blocked, image.Point{}, draw.Src)
+				continue
+			}
+			if p.isEmpty(p.grid[row][col]) {
+				draw.Draw(img, cell, empty, image.Point{}, draw.Src)
+				continue
+			}
+			draw.Draw(img, cell, pic.pieces[p.grid[row][col]], image.Point{}, draw.Src)
+
+			if highlight != nil && *highlight == here {
+				inner := cell.Inset(border)
+				for _, edge := range []image.Rectangle{
+					{cell.Min, image.Pt(cell.Max.X, inner.Min.Y)},
+					{image.Pt(cell.Min.X, inner.Max.Y), cell.Max},
+					{cell.Min, image.Pt(inner.Min.X, cell.Max.Y)},
+					{image.Pt(inner.Max.X, cell.Min.Y), cell.Max},
+				} {
+					draw.Draw(img, edge, outline, image.Point{}, draw.Src)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// paletted converts img to a paletted image suitable for GIF encoding.
+func paletted(img image.Image, p color.Palette) *image.Paletted {
+	dst := image.NewPaletted(img.Bounds(), p)
+	draw.FloydSteinberg.Draw(dst, dst.Rect, img, img.Bounds().Min)
+	return dst
+}
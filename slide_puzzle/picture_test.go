@@ -0,0 +1,141 @@
+package slide_puzzle
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// quadrantImage returns a 4x4 image with a distinct color in each 2x2 quadrant,
+// plus an extra column and row of excess pixels.
+func quadrantImage() (*image.RGBA, []color.RGBA) {
+	colors := []color.RGBA{
+		{0xff, 0, 0, 0xff},
+		{0, 0xff, 0, 0xff},
+		{0, 0, 0xff, 0xff},
+		{0xff, 0xff, 0, 0xff},
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 5, 5))
+	for y := range 5 {
+		for x := range 5 {
+			img.SetRGBA(x, y, colors[min(y/2, 1)*2+min(x/2, 1)])
+		}
+	}
+	return img, colors
+}
+
+func TestNewPicture(t *testing.T) {
+	t.Run("too small image returns error", func(t *testing.T) {
+		img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+		_, err := NewPicture(img, 3, 3)
+		var sizeErr *ImageSizeError
+		if !errors.As(err, &sizeErr) {
+			t.Fatalf("NewPicture() error type = %T, want *ImageSizeError", err)
+		}
+	})
+
+	t.Run("load from PNG", func(t *testing.T) {
+		img, _ := quadrantImage()
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			t.Fatalf("png.Encode() error: %v", err)
+		}
+		pic, err := LoadPicture(&buf, 2, 2)
+		if err != nil {
+			t.Fatalf("LoadPicture() error: %v", err)
+		}
+		if pic.tileSize != image.Pt(2, 2) {
+			t.Errorf("tile size = %v, want (2,2)", pic.tileSize)
+		}
+	})
+}
+
+func TestPictureRender(t *testing.T) {
+	img, colors := quadrantImage()
+	pic, err := NewPicture(img, 2, 2)
+	if err != nil {
+		t.Fatalf("NewPicture() error: %v", err)
+	}
+
+	t.Run("tiles are drawn at their positions", func(t *testing.T) {
+		grid := [][]int{
+			{3, 0},
+			{1, 2},
+		}
+		puzzle, err := NewPuzzle(grid, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+
+		got, err := pic.Render(*puzzle)
+		if err != nil {
+			t.Fatalf("Render() error: %v", err)
+		}
+		if got.Rect != image.Rect(0, 0, 4, 4) {
+			t.Fatalf("Render() size = %v, want 4x4", got.Rect)
+		}
+
+		want := map[image.Point]color.RGBA{
+			{0, 0}: colors[3],
+			{2, 0}: renderPalette[paletteEmpty].(color.RGBA),
+			{0, 2}: colors[1],
+			{2, 2}: colors[2],
+		}
+		for pt, c := range want {
+			if got.RGBAAt(pt.X, pt.Y) != c {
+				t.Errorf("pixel %v = %v, want %v", pt, got.RGBAAt(pt.X, pt.Y), c)
+			}
+		}
+	})
+
+	t.Run("frames outline the moved tile", func(t *testing.T) {
+		grid := [][]int{
+			{1, 0},
+			{2, 3},
+		}
+		puzzle, err := NewPuzzle(grid, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+
+		frames, err := pic.RenderFrames(*puzzle, []Move{East})
+		if err != nil {
+			t.Fatalf("RenderFrames() error: %v", err)
+		}
+		if len(frames) != 2 {
+			t.Fatalf("RenderFrames() returned %d frames, want 2", len(frames))
+		}
+		if got := frames[1].RGBAAt(2, 0); got != renderPalette[paletteHighlight] {
+			t.Errorf("moved tile pixel = %v, want highlight", got)
+		}
+	})
+
+	t.Run("mismatched puzzle size returns error", func(t *testing.T) {
+		puzzle, err := NewPuzzle(goalGrid(3, 3), 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		_, err = pic.Render(*puzzle)
+		var invalidErr *InvalidPuzzleError
+		if !errors.As(err, &invalidErr) {
+			t.Fatalf("Render() error type = %T, want *InvalidPuzzleError", err)
+		}
+	})
+
+	t.Run("render options use the picture", func(t *testing.T) {
+		puzzle, err := NewPuzzle(goalGrid(2, 2), 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		frames, err := RenderFrames(*puzzle, nil, RenderOptions{Picture: pic})
+		if err != nil {
+			t.Fatalf("RenderFrames() error: %v", err)
+		}
+		if frames[0].Rect != image.Rect(0, 0, 4, 4) {
+			t.Errorf("frame size = %v, want 4x4", frames[0].Rect)
+		}
+	})
+}
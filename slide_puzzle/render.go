@@ -0,0 +1,199 @@
+package slide_puzzle
+
+import (
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"io"
+	"strconv"
+)
+
+// RenderOptions controls how puzzle states are drawn as raster images.
+type RenderOptions struct {
+	// TileSize is the width and height of a single tile in pixels.
+	TileSize int
+	// FrameDelay is the delay between animation frames in 100ths of a second.
+	FrameDelay int
+	// Picture, if set, draws each board as a reassembled picture instead of
+	// numbered tiles. TileSize is ignored in favor of the picture's tile size;
+	// scale the image before slicing it to change that.
+	Picture *Picture
+}
+
+// DefaultRenderOptions returns the options used by the CLI.
+func DefaultRenderOptions() RenderOptions {
+	return RenderOptions{TileSize: 64, FrameDelay: 50}
+}
+
+// Palette indices used by rendered frames.
+const (
+	paletteBackground = iota
+	paletteTile
+	paletteHighlight
+	paletteText
+	paletteEmpty
+)
+
+var renderPalette = color.Palette{
+	paletteBackground: color.RGBA{0x33, 0x33, 0x33, 0xff},
+	paletteTile:       color.RGBA{0xee, 0xdd, 0xbb, 0xff},
+	paletteHighlight:  color.RGBA{0xff, 0xaa, 0x44, 0xff},
+	paletteText:       color.RGBA{0x22, 0x22, 0x22, 0xff},
+	paletteEmpty:      color.RGBA{0x55, 0x55, 0x55, 0xff},
+}
+
+// digitGlyphs is a 3x5 bitmap font for the digits 0-9. Each row is a 3-bit
+// mask, most significant bit on the left.
+var digitGlyphs = [10][5]uint8{
+	{7, 5, 5, 5, 7},
+	{2, 6, 2, 2, 7},
+	{7, 1, 7, 4, 7},
+	{7, 1, 7, 1, 7},
+	{5, 5, 7, 1, 1},
+	{7, 4, 7, 1, 7},
+	{7, 4, 7, 5, 7},
+	{7, 1, 1, 1, 1},
+	{7, 5, 7, 5, 7},
+	{7, 5, 7, 1, 7},
+}
+
+// minusGlyph is the minus sign for negative numbers, such as tile colors, in
+// the same font.
+var minusGlyph = [5]uint8{0, 0, 7, 0, 0}
+
+const (
+	glyphWidth   = 3
+	glyphHeight  = 5
+	glyphSpacing = 1
+)
+
+// RenderFrames draws the starting puzzle followed by the board after each move
+// in the given solution. In every frame but the first, the tile that just
+// moved is highlighted.
+func RenderFrames(p Puzzle, moves []Move, opts RenderOptions) ([]*image.Paletted, error) {
+	if opts.Picture != nil {
+		pictures, err := opts.Picture.RenderFrames(p, moves)
+		if err != nil {
+			return nil, err
+		}
+		frames := make([]*image.Paletted, len(pictures))
+		for i, picture := range pictures {
+			frames[i] = paletted(picture, palette.Plan9)
+		}
+		return frames, nil
+	}
+
+	if opts.TileSize <= 0 {
+		opts.TileSize = DefaultRenderOptions().TileSize
+	}
+
+	frames := make([]*image.Paletted, 0, len(moves)+1)
+	frames = append(frames, renderFrame(p, nil, opts.TileSize))
+
+	current := p
+	for _, move := range moves {
+		moved := current.emptyTile.coord
+		next, err := current.makeMove(move)
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, renderFrame(next, &moved, opts.TileSize))
+		current = next
+	}
+	return frames, nil
+}
+
+// WriteGIF encodes the solution as an animated GIF. See RenderFrames.
+func WriteGIF(w io.Writer, p Puzzle, moves []Move, opts RenderOptions) error {
+	frames, err := RenderFrames(p, moves, opts)
+	if err != nil {
+		return err
+	}
+	if opts.FrameDelay <= 0 {
+		opts.FrameDelay = DefaultRenderOptions().FrameDelay
+	}
+
+	anim := &gif.GIF{
+		Image: frames,
+		Delay: make([]int, len(frames)),
+	}
+	for i := range anim.Delay {
+		anim.Delay[i] = opts.FrameDelay
+	}
+	// Hold the solved board a little longer before looping.
+	anim.Delay[len(anim.Delay)-1] = 4 * opts.FrameDelay
+	return gif.EncodeAll(w, anim)
+}
+
+func renderFrame(p Puzzle, highlight *coord, tileSize int) *image.Paletted {
+	rows, cols := len(p.grid), len(p.grid[0])
+	img := image.NewPaletted(image.Rect(0, 0, cols*tileSize, rows*tileSize), renderPalette)
+
+	// Leave a thin border of background around each tile.
+	border := max(1, tileSize/32)
+	for row := range p.grid {
+		for col := range p.grid[row] {
+			cell := image.Rect(col*tileSize, row*tileSize, (col+1)*tileSize, (row+1)*tileSize)
+			fillRect(img, cell, paletteBackground)
+			if p.grid[row][col] == Blocked {
+				continue
+			}
+
+			inner := cell.Inset(border)
+			if p.isEmpty(p.grid[row][col]) {
+				fillRect(img, inner, paletteEmpty)
+				continue
+			}
+
+			fill := uint8(paletteTile)
+			if highlight != nil && *highlight == (coord{row: row, col: col}) {
+				fill = paletteHighlight
+			}
+			fillRect(img, inner, fill)
+			drawNumber(img, inner, p.color(p.grid[row][col]))
+		}
+	}
+	return img
+}
+
+func fillRect(img *image.Paletted, r image.Rectangle, index uint8) {
+	r = r.Intersect(img.Rect)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetColorIndex(x, y, index)
+		}
+	}
+}
+
+// drawNumber draws n centered in r, scaling the glyphs to fit.
+func drawNumber(img *image.Paletted, r image.Rectangle, n int) {
+	digits := strconv.Itoa(n)
+	textWidth := len(digits)*(glyphWidth+glyphSpacing) - glyphSpacing
+
+	// Use up to 70% of the width and 50% of the height of the tile.
+	scale := min(r.Dx()*7/10/textWidth, r.Dy()/2/glyphHeight)
+	if scale < 1 {
+		return
+	}
+
+	x0 := r.Min.X + (r.Dx()-textWidth*scale)/2
+	y0 := r.Min.Y + (r.Dy()-glyphHeight*scale)/2
+	for i, d := range digits {
+		glyph := minusGlyph
+		if d != '-' {
+			glyph = digitGlyphs[d-'0']
+		}
+		gx := x0 + i*(glyphWidth+glyphSpacing)*scale
+		for gy, bits := range glyph {
+			for bx := range glyphWidth {
+				if bits&(1<<(glyphWidth-1-bx)) == 0 {
+					continue
+				}
+				px := gx + bx*scale
+				py := y0 + gy*scale
+				fillRect(img, image.Rect(px, py, px+scale, py+scale), paletteText)
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package slide_puzzle
+
+import (
+	"bytes"
+	"errors"
+	"image/gif"
+	"testing"
+)
+
+func TestRenderFrames(t *testing.T) {
+	grid := [][]int{
+		{1, 2},
+		{0, 3},
+	}
+	puzzle, err := NewPuzzle(grid, 0)
+	if err != nil {
+		t.Fatalf("NewPuzzle() error: %v", err)
+	}
+
+	t.Run("one frame per state", func(t *testing.T) {
+		frames, err := RenderFrames(*puzzle, []Move{West, South}, RenderOptions{TileSize: 20})
+		if err != nil {
+			t.Fatalf("RenderFrames() error: %v", err)
+		}
+		if len(frames) != 3 {
+			t.Fatalf("RenderFrames() returned %d frames, want 3", len(frames))
+		}
+		for i, frame := range frames {
+			if frame.Rect.Dx() != 40 || frame.Rect.Dy() != 40 {
+				t.Errorf("frame %d has size %v, want 40x40", i, frame.Rect.Size())
+			}
+		}
+	})
+
+	t.Run("moved tile is highlighted", func(t *testing.T) {
+		frames, err := RenderFrames(*puzzle, []Move{West}, RenderOptions{TileSize: 20})
+		if err != nil {
+			t.Fatalf("RenderFrames() error: %v", err)
+		}
+
+		// Sample near the corner of each tile to avoid the digits.
+		if got := frames[0].ColorIndexAt(22, 22); got != paletteTile {
+			t.Errorf("first frame tile color index = %d, want %d", got, paletteTile)
+		}
+		// Tile 3 moved West into the bottom-left cell.
+		if got := frames[1].ColorIndexAt(2, 22); got != paletteHighlight {
+			t.Errorf("moved tile color index = %d, want %d", got, paletteHighlight)
+		}
+		if got := frames[1].ColorIndexAt(22, 22); got != paletteEmpty {
+			t.Errorf("empty tile color index = %d, want %d", got, paletteEmpty)
+		}
+	})
+
+	t.Run("negative colors get a minus sign", func(t *testing.T) {
+		colored, err := NewColoredPuzzle([][]int{{-1, -2}, {-5, -3}}, [][]int{{-1, -2}, {-3, -5}}, -5)
+		if err != nil {
+			t.Fatalf("NewColoredPuzzle() error: %v", err)
+		}
+		frames, err := RenderFrames(*colored, nil, RenderOptions{TileSize: 60})
+		if err != nil {
+			t.Fatalf("RenderFrames() error: %v", err)
+		}
+		// "-1" is drawn 6 pixels to a glyph pixel, starting at (9, 15), so
+		// the middle of the minus sign is at (18, 30).
+		if got := frames[0].ColorIndexAt(18, 30); got != paletteText {
+			t.Errorf("minus sign color index = %d, want %d", got, paletteText)
+		}
+	})
+
+	t.Run("invalid move returns error", func(t *testing.T) {
+		_, err := RenderFrames(*puzzle, []Move{East}, RenderOptions{})
+		var invalidErr *InvalidMoveError
+		if !errors.As(err, &invalidErr) {
+			t.Fatalf("RenderFrames() error type = %T, want *InvalidMoveError", err)
+		}
+	})
+}
+
+func TestWriteGIF(t *testing.T) {
+	grid := [][]int{
+		{1, 2, 0},
+		{3, 4, 5},
+	}
+	puzzle, err := NewPuzzle(grid, 0)
+	if err != nil {
+		t.Fatalf("NewPuzzle() error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteGIF(&buf, *puzzle, []Move{East, East}, DefaultRenderOptions()); err != nil {
+		t.Fatalf("WriteGIF() error: %v", err)
+	}
+
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("gif.DecodeAll() error: %v", err)
+	}
+	if len(anim.Image) != 3 {
+		t.Fatalf("GIF has %d frames, want 3", len(anim.Image))
+	}
+}
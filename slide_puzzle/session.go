@@ -0,0 +1,393 @@
+package slide_puzzle
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ActionKind is what a player did in a Session.
+type ActionKind int
+
+const (
+	MoveAction ActionKind = iota
+	UndoAction
+	RedoAction
+)
+
+var actionStrings = map[ActionKind]string{
+	MoveAction: "move",
+	UndoAction: "undo",
+	RedoAction: "redo",
+}
+
+func (k ActionKind) String() string {
+	return actionStrings[k]
+}
+
+// Action is one thing a player did in a Session.
+type Action struct {
+	Kind ActionKind
+	// Move is the move made, undone or redone.
+	Move Move
+	// At is the time since the session began.
+	At time.Duration
+}
+
+// Step is a move on the path from the start of a Session to its current
+// state.
+type Step struct {
+	Move Move
+	// Elapsed is the time the player took to make the move, since their
+	// previous action.
+	Elapsed time.Duration
+}
+
+// Frame is the state of a Session after one of its actions, for playing a
+// session back.
+type Frame struct {
+	Action Action
+	Puzzle Puzzle
+}
+
+// InvalidReplayError is returned when reading a replay that is not in the
+// expected format or does not play back.
+type InvalidReplayError struct {
+	msg string
+}
+
+func (e InvalidReplayError) Error() string {
+	return e.msg
+}
+
+// replayHeader starts every replay file, followed by a format version.
+const replayHeader = "slide-puzzle-replay"
+
+const replayVersion = 1
+
+// Session is a game in progress: a puzzle along with every move the player
+// made, undid and redid, and when.
+//
+// Its history is a tree. Undoing a move and then making a different one
+// starts a new branch rather than discarding the moves undone, so Redo can
+// still return to them; Redo follows the branch visited most recently, and
+// Branches lists the others.
+//
+// The zero Session has no puzzle: it is never solved, and every move is
+// invalid. Use NewSession to start one. A Session is not safe for concurrent
+// use.
+type Session struct {
+	start, current Puzzle
+	// root is the start of the history, and node the current state's place
+	// in it.
+	root, node *sessionNode
+	actions    []Action
+	began      time.Time
+	now        func() time.Time
+}
+
+// sessionNode is a state in a Session's history, reached from its parent by
+// step.
+type sessionNode struct {
+	parent *sessionNode
+	step   Step
+	// children are the states reached from this one, the one visited most
+	// recently last.
+	children []*sessionNode
+}
+
+// NewSession starts a session on p, which must have a single empty tile. The
+// clock starts now.
+func NewSession(p Puzzle) (*Session, error) {
+	return newSession(p, time.Now)
+}
+
+func newSession(p Puzzle, now func() time.Time) (*Session, error) {
+	if p.Rows() == 0 {
+		return nil, &InvalidPuzzleError{"cannot start a session without a puzzle"}
+	}
+	if err := p.requireSingleEmpty(); err != nil {
+		return nil, err
+	}
+	root := &sessionNode{}
+	return &Session{start: p, current: p, root: root, node: root, began: now(), now: now}, nil
+}
+
+// Move makes move m, starting a new branch of the history unless m is the
+// move Redo or one of Branches would make, in which case that branch is
+// followed and kept.
+func (s *Session) Move(m Move) error {
+	return s.move(m, s.since())
+}
+
+// Undo takes back the last move. It returns false if there is none.
+func (s *Session) Undo() bool {
+	return s.undo(s.since())
+}
+
+// Redo makes the move last undone from the current state again. It returns
+// false if there is none.
+func (s *Session) Redo() bool {
+	return s.redo(s.since())
+}
+
+func (s *Session) since() time.Duration {
+	if s.now == nil {
+		// The zero Session has no clock.
+		return 0
+	}
+	return s.now().Sub(s.began)
+}
+
+func (s *Session) move(m Move, at time.Duration) error {
+	next, err := s.current.makeMove(m)
+	if err != nil {
+		return err
+	}
+	child := &sessionNode{parent: s.node}
+	if i := slices.IndexFunc(s.node.children, func(c *sessionNode) bool { return c.step.Move == m }); i >= 0 {
+		child = s.node.children[i]
+		s.node.children = slices.Delete(s.node.children, i, i+1)
+	}
+	child.step = Step{Move: m, Elapsed: at - s.lastAt()}
+	s.node.children = append(s.node.children, child)
+	s.node, s.current = child, next
+	s.actions = append(s.actions, Action{Kind: MoveAction, Move: m, At: at})
+	return nil
+}
+
+func (s *Session) undo(at time.Duration) bool {
+	if s.node == s.root {
+		return false
+	}
+	m := s.node.step.Move
+	// Every move can be undone by the opposite one.
+	s.current, _ = s.current.makeMove(m.opposite())
+	s.node = s.node.parent
+	s.actions = append(s.actions, Action{Kind: UndoAction, Move: m, At: at})
+	return true
+}
+
+func (s *Session) redo(at time.Duration) bool {
+	if s.node == nil || len(s.node.children) == 0 {
+		return false
+	}
+	child := s.node.children[len(s.node.children)-1]
+	// The move was made from this state before, so it is still valid.
+	s.current, _ = s.current.makeMove(child.step.Move)
+	s.node = child
+	s.actions = append(s.actions, Action{Kind: RedoAction, Move: child.step.Move, At: at})
+	return true
+}
+
+// lastAt returns the time of the last action, or 0 if there is none.
+func (s *Session) lastAt() time.Duration {
+	if len(s.actions) == 0 {
+		return 0
+	}
+	return s.actions[len(s.actions)-1].At
+}
+
+// Start returns the puzzle the session started from.
+func (s *Session) Start() Puzzle {
+	return s.start
+}
+
+// Puzzle returns the current state of the puzzle.
+func (s *Session) Puzzle() Puzzle {
+	return s.current
+}
+
+// Solved reports whether the current state is solved. It is false for the
+// zero Session, which has no puzzle.
+func (s *Session) Solved() bool {
+	return s.current.Rows() > 0 && s.current.isSolved()
+}
+
+// History returns the moves from the start to the current state, with the
+// time each took.
+func (s *Session) History() []Step {
+	var steps []Step
+	for n := s.node; n != s.root; n = n.parent {
+		steps = append(steps, n.step)
+	}
+	slices.Reverse(steps)
+	return steps
+}
+
+// Moves returns the moves from the start to the current state.
+func (s *Session) Moves() []Move {
+	steps := s.History()
+	moves := make([]Move, len(steps))
+	for i, step := range steps {
+		moves[i] = step.Move
+	}
+	return moves
+}
+
+// Branches returns the moves that lead back into previously visited parts of
+// the history from the current state, the one Redo would make first. Passing
+// any of them to Move follows that branch.
+func (s *Session) Branches() []Move {
+	if s.node == nil {
+		return []Move{}
+	}
+	moves := make([]Move, 0, len(s.node.children))
+	for _, c := range slices.Backward(s.node.children) {
+		moves = append(moves, c.step.Move)
+	}
+	return moves
+}
+
+// Actions returns everything the player did, in order.
+func (s *Session) Actions() []Action {
+	return slices.Clone(s.actions)
+}
+
+// Elapsed returns the time since the session began. For sessions read with
+// ReadSession, the clock continues from the last action, leaving out the time
+// the session was saved for.
+func (s *Session) Elapsed() time.Duration {
+	return s.since()
+}
+
+// Replay plays the session back from the start, returning the state after
+// each action. A frontend can show each frame at its Action.At to replay the
+// game as it was played.
+func (s *Session) Replay() []Frame {
+	current := s.start
+	frames := make([]Frame, len(s.actions))
+	for i, a := range s.actions {
+		m := a.Move
+		if a.Kind == UndoAction {
+			m = m.opposite()
+		}
+		// Every action was valid when it was recorded.
+		current, _ = current.makeMove(m)
+		frames[i] = Frame{Action: a, Puzzle: current}
+	}
+	return frames
+}
+
+// WriteTo writes the session to w as a replay file. The format is text: a
+// header line with the format version, the starting puzzle as encoded by
+// Puzzle.MarshalText, and then one line per action with its kind, move and
+// time since the session began, e.g. "move North 1.5s".
+func (s *Session) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	text, err := s.start.MarshalText()
+	if err != nil {
+		return 0, err
+	}
+	fmt.Fprintf(&b, "%s %d\npuzzle %s\n", replayHeader, replayVersion, text)
+	for _, a := range s.actions {
+		fmt.Fprintf(&b, "%s %s %s\n", a.Kind, a.Move, a.At)
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ReadSession reads a session written by Session.WriteTo, playing back its
+// actions to rebuild its history.
+func ReadSession(r io.Reader) (*Session, error) {
+	scanner := bufio.NewScanner(r)
+	line := 0
+	next := func() (string, bool) {
+		for scanner.Scan() {
+			line++
+			if text := strings.TrimSpace(scanner.Text()); text != "" {
+				return text, true
+			}
+		}
+		return "", false
+	}
+
+	header, _ := next()
+	if header != fmt.Sprintf("%s %d", replayHeader, replayVersion) {
+		if strings.HasPrefix(header, replayHeader+" ") {
+			return nil, &InvalidReplayError{fmt.Sprintf("unsupported replay version %q", strings.TrimPrefix(header, replayHeader+" "))}
+		}
+		return nil, &InvalidReplayError{"not a replay file"}
+	}
+	text, _ := next()
+	puzzleText, ok := strings.CutPrefix(text, "puzzle ")
+	if !ok {
+		return nil, &InvalidReplayError{fmt.Sprintf("line %d: want the starting puzzle", line)}
+	}
+	var start Puzzle
+	if err := start.UnmarshalText([]byte(puzzleText)); err != nil {
+		return nil, &InvalidReplayError{fmt.Sprintf("line %d: %v", line, err)}
+	}
+	s, err := newSession(start, time.Now)
+	if err != nil {
+		return nil, &InvalidReplayError{fmt.Sprintf("line %d: %v", line, err)}
+	}
+
+	for text, ok := next(); ok; text, ok = next() {
+		a, err := parseAction(text)
+		if err == nil && a.At < s.lastAt() {
+			err = fmt.Errorf("time %s is before the previous action", a.At)
+		}
+		if err == nil {
+			err = s.apply(a)
+		}
+		if err != nil {
+			return nil, &InvalidReplayError{fmt.Sprintf("line %d: %v", line, err)}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	// Carry on from the last action.
+	s.began = s.now().Add(-s.lastAt())
+	return s, nil
+}
+
+// apply repeats action a, checking that it does what it says.
+func (s *Session) apply(a Action) error {
+	switch a.Kind {
+	case MoveAction:
+		return s.move(a.Move, a.At)
+	case UndoAction:
+		if s.node == s.root || s.node.step.Move != a.Move {
+			return fmt.Errorf("cannot undo %s", a.Move)
+		}
+		s.undo(a.At)
+	case RedoAction:
+		if branches := s.Branches(); len(branches) == 0 || branches[0] != a.Move {
+			return fmt.Errorf("cannot redo %s", a.Move)
+		}
+		s.redo(a.At)
+	}
+	return nil
+}
+
+// parseAction parses an action line written by Session.WriteTo.
+func parseAction(text string) (Action, error) {
+	fields := strings.Fields(text)
+	if len(fields) != 3 {
+		return Action{}, fmt.Errorf("invalid action %q; want kind, move and time", text)
+	}
+	var a Action
+	found := false
+	for kind, name := range actionStrings {
+		if fields[0] == name {
+			a.Kind, found = kind, true
+		}
+	}
+	if !found {
+		return Action{}, fmt.Errorf("unknown action %q", fields[0])
+	}
+	if err := a.Move.UnmarshalText([]byte(fields[1])); err != nil {
+		return Action{}, err
+	}
+	at, err := time.ParseDuration(fields[2])
+	if err != nil || at < 0 {
+		return Action{}, fmt.Errorf("invalid time %q", fields[2])
+	}
+	a.At = at
+	return a, nil
+}
@@ -0,0 +1,244 @@
+package slide_puzzle
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// newTestSession returns a session on a 3x3 puzzle with the empty tile in the
+// middle, whose clock advances a second each time it is read.
+func newTestSession(t *testing.T) *Session {
+	t.Helper()
+	p, err := NewPuzzle([][]int{{1, 2, 3}, {4, 0, 5}, {6, 7, 8}}, 0)
+	if err != nil {
+		t.Fatalf("NewPuzzle() error: %v", err)
+	}
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s, err := newSession(*p, func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	})
+	if err != nil {
+		t.Fatalf("newSession() error: %v", err)
+	}
+	return s
+}
+
+func TestSession(t *testing.T) {
+	t.Run("undo and redo", func(t *testing.T) {
+		s := newTestSession(t)
+		for _, m := range []Move{North, West} {
+			if err := s.Move(m); err != nil {
+				t.Fatalf("Move(%v) error: %v", m, err)
+			}
+		}
+		after := s.Puzzle()
+		if !s.Undo() || !s.Undo() {
+			t.Fatalf("Undo() = false with moves to undo")
+		}
+		if !s.Puzzle().Equal(s.Start()) {
+			t.Errorf("Puzzle() after undoing every move = %v, want %v", s.Puzzle(), s.Start())
+		}
+		if !s.Redo() || !s.Redo() {
+			t.Fatalf("Redo() = false with moves to redo")
+		}
+		if !s.Puzzle().Equal(after) {
+			t.Errorf("Puzzle() after redoing = %v, want %v", s.Puzzle(), after)
+		}
+		want := []Step{{Move: North, Elapsed: time.Second}, {Move: West, Elapsed: time.Second}}
+		if diff := cmp.Diff(want, s.History()); diff != "" {
+			t.Errorf("History() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("nothing to undo or redo", func(t *testing.T) {
+		s := newTestSession(t)
+		if s.Undo() || s.Redo() {
+			t.Errorf("Undo() or Redo() = true at the start")
+		}
+		if got := len(s.Actions()); got != 0 {
+			t.Errorf("len(Actions()) = %d, want 0", got)
+		}
+	})
+
+	t.Run("branches", func(t *testing.T) {
+		s := newTestSession(t)
+		for _, m := range []Move{North, West} {
+			if err := s.Move(m); err != nil {
+				t.Fatalf("Move(%v) error: %v", m, err)
+			}
+		}
+		s.Undo()
+		s.Undo()
+		if err := s.Move(South); err != nil {
+			t.Fatalf("Move(South) error: %v", err)
+		}
+		s.Undo()
+		if diff := cmp.Diff([]Move{South, North}, s.Branches()); diff != "" {
+			t.Errorf("Branches() mismatch (-want +got):\n%s", diff)
+		}
+
+		// Going back into the old branch keeps the moves made in it.
+		if err := s.Move(North); err != nil {
+			t.Fatalf("Move(North) error: %v", err)
+		}
+		if diff := cmp.Diff([]Move{West}, s.Branches()); diff != "" {
+			t.Errorf("Branches() after following a branch mismatch (-want +got):\n%s", diff)
+		}
+		s.Redo()
+		if diff := cmp.Diff([]Move{North, West}, s.Moves()); diff != "" {
+			t.Errorf("Moves() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("invalid move is not recorded", func(t *testing.T) {
+		s := newTestSession(t)
+		if err := s.Move(North); err != nil {
+			t.Fatalf("Move(North) error: %v", err)
+		}
+		err := s.Move(North)
+		var moveErr *InvalidMoveError
+		if !errors.As(err, &moveErr) {
+			t.Errorf("Move() error type = %T, want *InvalidMoveError", err)
+		}
+		if got := len(s.Actions()); got != 1 {
+			t.Errorf("len(Actions()) = %d, want 1", got)
+		}
+	})
+
+	t.Run("solved", func(t *testing.T) {
+		goal, err := Goal(2, 2, 0)
+		if err != nil {
+			t.Fatalf("Goal() error: %v", err)
+		}
+		s, err := NewSession(*goal)
+		if err != nil {
+			t.Fatalf("NewSession() error: %v", err)
+		}
+		if !s.Solved() {
+			t.Errorf("Solved() = false at the goal")
+		}
+		if err := s.Move(North); err != nil {
+			t.Fatalf("Move(North) error: %v", err)
+		}
+		if s.Solved() {
+			t.Errorf("Solved() = true after moving away from the goal")
+		}
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		var s Session
+		if s.Solved() {
+			t.Errorf("Solved() = true for the zero Session")
+		}
+		var moveErr *InvalidMoveError
+		if err := s.Move(North); !errors.As(err, &moveErr) {
+			t.Errorf("Move() error type = %T, want *InvalidMoveError", err)
+		}
+		if s.Undo() || s.Redo() {
+			t.Errorf("Undo() or Redo() = true for the zero Session")
+		}
+		if got := len(s.Branches()) + len(s.History()) + len(s.Actions()); got != 0 {
+			t.Errorf("zero Session has %d branches, steps and actions, want none", got)
+		}
+		if got := s.Elapsed(); got != 0 {
+			t.Errorf("Elapsed() = %v, want 0", got)
+		}
+	})
+
+	t.Run("zero puzzle returns error", func(t *testing.T) {
+		_, err := NewSession(Puzzle{})
+		var invalidErr *InvalidPuzzleError
+		if !errors.As(err, &invalidErr) {
+			t.Errorf("NewSession() error type = %T, want *InvalidPuzzleError", err)
+		}
+	})
+
+	t.Run("several empty tiles return error", func(t *testing.T) {
+		p, err := NewPuzzleWithHoles([][]int{{1, 2, 3}, {4, 0, 5}}, []int{0, 5})
+		if err != nil {
+			t.Fatalf("NewPuzzleWithHoles() error: %v", err)
+		}
+		_, err = NewSession(*p)
+		var invalidErr *InvalidPuzzleError
+		if !errors.As(err, &invalidErr) {
+			t.Errorf("NewSession() error type = %T, want *InvalidPuzzleError", err)
+		}
+	})
+}
+
+func TestSessionReplay(t *testing.T) {
+	s := newTestSession(t)
+	for _, m := range []Move{North, West, South} {
+		if err := s.Move(m); err != nil {
+			t.Fatalf("Move(%v) error: %v", m, err)
+		}
+	}
+	s.Undo()
+	s.Undo()
+	if err := s.Move(East); err != nil {
+		t.Fatalf("Move(East) error: %v", err)
+	}
+	s.Undo()
+	s.Redo()
+
+	t.Run("frames", func(t *testing.T) {
+		frames := s.Replay()
+		if len(frames) != len(s.Actions()) {
+			t.Fatalf("len(Replay()) = %d, want %d", len(frames), len(s.Actions()))
+		}
+		if last := frames[len(frames)-1].Puzzle; !last.Equal(s.Puzzle()) {
+			t.Errorf("last frame = %v, want %v", last, s.Puzzle())
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		var buf bytes.Buffer
+		if _, err := s.WriteTo(&buf); err != nil {
+			t.Fatalf("WriteTo() error: %v", err)
+		}
+		got, err := ReadSession(&buf)
+		if err != nil {
+			t.Fatalf("ReadSession() error: %v", err)
+		}
+		if diff := cmp.Diff(s.Actions(), got.Actions()); diff != "" {
+			t.Errorf("Actions() mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(s.History(), got.History()); diff != "" {
+			t.Errorf("History() mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(s.Branches(), got.Branches()); diff != "" {
+			t.Errorf("Branches() mismatch (-want +got):\n%s", diff)
+		}
+		if !got.Puzzle().Equal(s.Puzzle()) {
+			t.Errorf("Puzzle() = %v, want %v", got.Puzzle(), s.Puzzle())
+		}
+	})
+
+	start := "slide-puzzle-replay 1\npuzzle grid=1 2 3/4 0 5/6 7 8; empty=0\n"
+	for name, text := range map[string]string{
+		"not a replay":     "grid=1 0/2 3\n",
+		"future version":   "slide-puzzle-replay 9\n",
+		"missing puzzle":   "slide-puzzle-replay 1\nmove North 1s\n",
+		"unknown action":   start + "jump North 1s\n",
+		"invalid move":     start + "move North 1s\nmove North 2s\n",
+		"wrong undo":       start + "move North 1s\nundo West 2s\n",
+		"nothing to redo":  start + "redo North 1s\n",
+		"time goes back":   start + "move North 2s\nmove West 1s\n",
+		"invalid time":     start + "move North soon\n",
+		"missing the time": start + "move North\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ReadSession(strings.NewReader(text))
+			var replayErr *InvalidReplayError
+			if !errors.As(err, &replayErr) {
+				t.Errorf("ReadSession(%q) error type = %T, want *InvalidReplayError", text, err)
+			}
+		})
+	}
+}
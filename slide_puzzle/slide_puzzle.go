@@ -1,10 +1,20 @@
 package slide_puzzle
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+)
 
 type Puzzle struct {
 	grid      [][]int
 	emptyTile tile
+	// holes are any empty tiles besides emptyTile. See NewPuzzleWithHoles.
+	holes []tile
+	// colors, if set, gives the color of each tile value. See
+	// NewColoredPuzzle.
+	colors []int
+	// wildcards, if set, marks the cells whose goal accepts any tile.
+	wildcards []bool
 }
 
 type tile struct {
@@ -47,6 +57,9 @@ const (
 	West
 )
 
+// allMoves lists every move in a fixed order, for when iteration order matters.
+var allMoves = []Move{North, South, East, West}
+
 var moveStrings = map[Move]string{
 	North: "North",
 	South: "South",
@@ -58,6 +71,40 @@ func (m Move) String() string {
 	return moveStrings[m]
 }
 
+// opposite returns the move that undoes m.
+func (m Move) opposite() Move {
+	switch m {
+	case North:
+		return South
+	case South:
+		return North
+	case East:
+		return West
+	default:
+		return East
+	}
+}
+
+// source returns the position of the tile that slides into the empty space at
+// empty when making move m.
+func (m Move) source(empty coord) coord {
+	switch m {
+	case North:
+		// Move tile from south up
+		return coord{row: empty.row + 1, col: empty.col}
+	case South:
+		// Move tile from north down
+		return coord{row: empty.row - 1, col: empty.col}
+	case East:
+		// Move tile from west right
+		return coord{row: empty.row, col: empty.col - 1}
+	case West:
+		// Move tile from east left
+		return coord{row: empty.row, col: empty.col + 1}
+	}
+	return empty
+}
+
 func NewPuzzle(grid [][]int, emptyTileValue int) (*Puzzle, error) {
 	if len(grid) == 0 {
 		return nil, &InvalidPuzzleError{"puzzle must have at least one row"}
@@ -82,6 +129,9 @@ func NewPuzzle(grid [][]int, emptyTileValue int) (*Puzzle, error) {
 		}
 		for col := range grid[row] {
 			val := grid[row][col]
+			if val == Blocked {
+				continue
+			}
 
 			// Check if value is in valid range
 			if val < 0 || val >= numTiles {
@@ -105,26 +155,45 @@ func NewPuzzle(grid [][]int, emptyTileValue int) (*Puzzle, error) {
 		return nil, &InvalidPuzzleError{fmt.Sprintf("puzzle must contain exactly one empty tile; got %d", emptyCount)}
 	}
 
+	// Values name goal positions, so none can belong in a blocked cell.
+	for row := range grid {
+		for col := range grid[row] {
+			if val := row*rowLength + col; grid[row][col] == Blocked && seen[val] {
+				return nil, &InvalidPuzzleError{fmt.Sprintf("value %d belongs in blocked cell (%d, %d)", val, row, col)}
+			}
+		}
+	}
+
 	return &Puzzle{grid: grid, emptyTile: emptyTile}, nil
 }
 
+// Rows returns the number of rows in the puzzle.
+func (p Puzzle) Rows() int {
+	return len(p.grid)
+}
+
+// Cols returns the number of columns in the puzzle.
+func (p Puzzle) Cols() int {
+	return len(p.grid[0])
+}
+
+// Values returns the tile values in row-major order.
+func (p Puzzle) Values() []int {
+	values := make([]int, 0, p.Rows()*p.Cols())
+	for _, row := range p.grid {
+		values = append(values, row...)
+	}
+	return values
+}
+
 func (p Puzzle) getMoves() map[Move]bool {
 	moves := make(map[Move]bool)
-	// North: move tile from south up
-	if p.emptyTile.coord.row < (len(p.grid) - 1) {
-		moves[North] = true
-	}
-	// South: move tile from north down
-	if p.emptyTile.coord.row > 0 {
-		moves[South] = true
-	}
-	// East: move tile from west right
-	if p.emptyTile.coord.col > 0 {
-		moves[East] = true
-	}
-	// West: move tile from east left
-	if p.emptyTile.coord.col < (len(p.grid[0]) - 1) {
-		moves[West] = true
+	// A move is possible when the tile it slides is on the board and not
+	// blocked, e.g. North needs a tile south of the empty space.
+	for _, m := range allMoves {
+		if p.isOpen(m.source(p.emptyTile.coord)) {
+			moves[m] = true
+		}
 	}
 	return moves
 }
@@ -133,7 +202,9 @@ func (p Puzzle) isSolved() bool {
 	want := 0
 	for row := range p.grid {
 		for col := range p.grid[row] {
-			if p.grid[row][col] != want {
+			// Empty tiles are interchangeable, as are tiles of the same color,
+			// and wildcard cells take any tile.
+			if val := p.grid[row][col]; val != Blocked && !p.accepts(want, val) && !(p.isEmpty(val) && p.isEmpty(want)) {
 				return false
 			}
 			want++
@@ -164,44 +235,50 @@ func (p Puzzle) makeMove(m Move) (Puzzle, error) {
 
 	// Determine the target tile based on move direction
 	// Move direction refers to the tile moving, not the empty tile.
-	var targetRow, targetCol int
-	switch m {
-	case North:
-		// Move tile from south up
-		targetRow = p.emptyTile.coord.row + 1
-		targetCol = p.emptyTile.coord.col
-	case South:
-		// Move tile from north down
-		targetRow = p.emptyTile.coord.row - 1
-		targetCol = p.emptyTile.coord.col
-	case East:
-		// Move tile from west right
-		targetRow = p.emptyTile.coord.row
-		targetCol = p.emptyTile.coord.col - 1
-	case West:
-		// Move tile from east left
-		targetRow = p.emptyTile.coord.row
-		targetCol = p.emptyTile.coord.col + 1
-	}
+	target := m.source(p.emptyTile.coord)
+	targetRow, targetCol := target.row, target.col
 
 	// Swap the empty tile with the target tile.
 	newGrid[p.emptyTile.coord.row][p.emptyTile.coord.col] = newGrid[targetRow][targetCol]
 	newGrid[targetRow][targetCol] = p.emptyTile.value
 
+	// The tile moved may be another empty tile.
+	holes := slices.Clone(p.holes)
+	for i := range holes {
+		if holes[i].coord == target {
+			holes[i].coord = p.emptyTile.coord
+		}
+	}
+
 	return Puzzle{
 		grid: newGrid,
 		emptyTile: tile{
 			value: p.emptyTile.value,
 			coord: coord{row: targetRow, col: targetCol},
 		},
+		holes:     holes,
+		colors:    p.colors,
+		wildcards: p.wildcards,
 	}, nil
 }
 
 func (p Puzzle) String() string {
+	if p.colors != nil {
+		// Tiles of the same color are interchangeable, so leave out which is
+		// which.
+		return fmt.Sprintf("colors=%v; empty=%v", p.Colors(), p.color(p.emptyTile.value))
+	}
+	if len(p.holes) > 0 {
+		return fmt.Sprintf("grid=%v; empty=%v", p.grid, p.EmptyValues())
+	}
 	return fmt.Sprintf("grid=%v; empty=%v", p.grid, p.emptyTile.value)
 }
 
 func (p Puzzle) Solve() ([]Move, error) {
+	if err := p.requireSingleEmpty(); err != nil {
+		return nil, err
+	}
+
 	// Check if already solved
 	if p.isSolved() {
 		return []Move{}, nil
@@ -0,0 +1,86 @@
+package slide_puzzle
+
+// isSolvable reports whether the goal state can be reached from p.
+//
+// On boards with at least two rows and two columns, every move swaps the empty
+// tile with a neighbor, which flips the parity of the permutation of all tiles
+// (including the empty one) and moves the empty tile one step. The goal is
+// therefore reachable exactly when the permutation parity matches the parity
+// of the empty tile's distance from its goal position.
+//
+// On a single row or column, tiles can never pass each other, so the goal is
+// reachable exactly when the other tiles are already in order.
+//
+// With colored tiles, on a single row or column the colors must be in order,
+// with wildcard cells matching any color. On larger boards, two tiles of the
+// same color, or two in wildcard cells, can be swapped to fix the parity, so
+// any arrangement can be solved.
+//
+// With blocked cells, see Solvability. Puzzles whose solvability is unknown
+// are reported as solvable, leaving it to the search to find out.
+func (p Puzzle) isSolvable() bool {
+	if p.hasBlocked() {
+		return p.Solvability() != Unsolvable
+	}
+	rows, cols := len(p.grid), len(p.grid[0])
+	values := p.Values()
+
+	if p.colors != nil {
+		if rows == 1 || cols == 1 {
+			return p.lineSolvable()
+		}
+		if p.hasTwins() || p.hasLooseEmpty() {
+			return true
+		}
+	}
+	if rows == 1 || cols == 1 {
+		prev := -1
+		for _, val := range values {
+			if val == p.emptyTile.value {
+				continue
+			}
+			if val < prev {
+				return false
+			}
+			prev = val
+		}
+		return true
+	}
+
+	return permutationParity(values) == p.emptyDistance()%2
+}
+
+// permutationParity returns the parity of the permutation that maps each cell
+// to the goal position of the tile on it. Blocked cells are left in place.
+func permutationParity(values []int) int {
+	cycles := 0
+	seen := make([]bool, len(values))
+	for start := range values {
+		if seen[start] {
+			continue
+		}
+		cycles++
+		for i := start; !seen[i]; {
+			seen[i] = true
+			if values[i] != Blocked {
+				i = values[i]
+			}
+		}
+	}
+	return (len(values) - cycles) % 2
+}
+
+// emptyDistance returns the Manhattan distance of the empty tile from its goal
+// position.
+func (p Puzzle) emptyDistance() int {
+	cols := len(p.grid[0])
+	goalRow, goalCol := p.emptyTile.value/cols, p.emptyTile.value%cols
+	return abs(p.emptyTile.coord.row-goalRow) + abs(p.emptyTile.coord.col-goalCol)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
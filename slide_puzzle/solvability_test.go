@@ -0,0 +1,57 @@
+package slide_puzzle
+
+import (
+	"errors"
+	"testing"
+)
+
+// permutations returns every ordering of 0..n-1.
+func permutations(n int) [][]int {
+	if n == 0 {
+		return [][]int{{}}
+	}
+	var result [][]int
+	for _, perm := range permutations(n - 1) {
+		for i := 0; i <= len(perm); i++ {
+			next := make([]int, 0, n)
+			next = append(next, perm[:i]...)
+			next = append(next, n-1)
+			next = append(next, perm[i:]...)
+			result = append(result, next)
+		}
+	}
+	return result
+}
+
+func gridFromValues(rows, cols int, values []int) [][]int {
+	grid := make([][]int, rows)
+	for row := range grid {
+		grid[row] = values[row*cols : (row+1)*cols]
+	}
+	return grid
+}
+
+func TestIsSolvable(t *testing.T) {
+	// Check the parity rule against exhaustive search for every arrangement of
+	// some small boards, with the empty tile's goal in different places.
+	boards := []struct {
+		rows, cols, empty int
+	}{
+		{2, 3, 0}, {3, 2, 3}, {2, 2, 1}, {1, 4, 0}, {4, 1, 2},
+	}
+
+	for _, b := range boards {
+		for _, perm := range permutations(b.rows * b.cols) {
+			puzzle, err := NewPuzzle(gridFromValues(b.rows, b.cols, perm), b.empty)
+			if err != nil {
+				t.Fatalf("NewPuzzle() error: %v", err)
+			}
+
+			_, err = puzzle.Solve()
+			want := !errors.As(err, &UnsolvablePuzzleError{})
+			if got := puzzle.isSolvable(); got != want {
+				t.Errorf("isSolvable() = %v for %v, want %v", got, puzzle, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package slide_puzzle
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SVGOptions controls how puzzles and solutions are drawn as SVG documents.
+// Colors are any value accepted by the SVG fill and stroke attributes.
+type SVGOptions struct {
+	// TileSize is the width and height of a single tile in user units.
+	TileSize int
+	// Columns is the number of boards per row in a solution diagram.
+	Columns int
+	// Convention sets how moves are labeled, and whether arrows show the
+	// tile moving or the empty space moving.
+	Convention Convention
+
+	BackgroundColor string
+	TileColor       string
+	EmptyColor      string
+	HighlightColor  string
+	TextColor       string
+	ArrowColor      string
+}
+
+// DefaultSVGOptions returns the options used by the CLI.
+func DefaultSVGOptions() SVGOptions {
+	return SVGOptions{
+		TileSize:        40,
+		Columns:         4,
+		BackgroundColor: "#333333",
+		TileColor:       "#eeddbb",
+		EmptyColor:      "#555555",
+		HighlightColor:  "#ffaa44",
+		TextColor:       "#222222",
+		ArrowColor:      "#cc2222",
+	}
+}
+
+// withDefaults fills in any unset options from DefaultSVGOptions.
+func (o SVGOptions) withDefaults() SVGOptions {
+	d := DefaultSVGOptions()
+	if o.TileSize <= 0 {
+		o.TileSize = d.TileSize
+	}
+	if o.Columns <= 0 {
+		o.Columns = d.Columns
+	}
+	for _, c := range []struct {
+		opt *string
+		def string
+	}{
+		{&o.BackgroundColor, d.BackgroundColor},
+		{&o.TileColor, d.TileColor},
+		{&o.EmptyColor, d.EmptyColor},
+		{&o.HighlightColor, d.HighlightColor},
+		{&o.TextColor, d.TextColor},
+		{&o.ArrowColor, d.ArrowColor},
+	} {
+		if *c.opt == "" {
+			*c.opt = c.def
+		}
+	}
+	return o
+}
+
+// WritePuzzleSVG writes a single board as an SVG document.
+func WritePuzzleSVG(w io.Writer, p Puzzle, opts SVGOptions) error {
+	opts = opts.withDefaults()
+	width, height := boardSize(p, opts)
+
+	bw := bufio.NewWriter(w)
+	writeSVGHeader(bw, width, height, opts)
+	writeBoard(bw, p, 0, 0, nil, opts)
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// WriteSolutionSVG writes a step-by-step diagram of a solution as an SVG
+// document. Each step shows the board before a move, with the tile about to
+// move highlighted and an arrow pointing in the direction it slides, or with
+// the BlankMoves convention, the direction the empty space moves. The final
+// board shows the solved puzzle.
+func WriteSolutionSVG(w io.Writer, p Puzzle, moves []Move, opts SVGOptions) error {
+	opts = opts.withDefaults()
+
+	// Validate the solution up front so we never emit a partial document.
+	boards := make([]Puzzle, 0, len(moves)+1)
+	boards = append(boards, p)
+	for _, move := range moves {
+		next, err := boards[len(boards)-1].makeMove(move)
+		if err != nil {
+			return err
+		}
+		boards = append(boards, next)
+	}
+
+	boardWidth, boardHeight := boardSize(p, opts)
+	gap := opts.TileSize / 2
+	caption := opts.TileSize / 2
+	cellWidth := boardWidth + gap
+	cellHeight := boardHeight + caption + gap
+
+	columns := min(opts.Columns, len(boards))
+	rows := (len(boards) + columns - 1) / columns
+
+	bw := bufio.NewWriter(w)
+	writeSVGHeader(bw, columns*cellWidth+gap, rows*cellHeight+gap, opts)
+	for i, board := range boards {
+		x := gap + (i%columns)*cellWidth
+		y := gap + (i/columns)*cellHeight
+
+		var label string
+		var move *Move
+		if i < len(moves) {
+			label = fmt.Sprintf("%d. %s", i+1, moves[i].Convert(TileMoves, opts.Convention))
+			move = &moves[i]
+		} else {
+			label = "Solved"
+		}
+		fmt.Fprintf(bw, `<text x="%d" y="%d" font-family="sans-serif" font-size="%d" fill="%s">%s</text>`+"\n",
+			x, y+caption*3/4, caption*3/4, escapeXML(opts.TextColor), escapeXML(label))
+		writeBoard(bw, board, x, y+caption, move, opts)
+	}
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+func boardSize(p Puzzle, opts SVGOptions) (width, height int) {
+	return len(p.grid[0]) * opts.TileSize, len(p.grid) * opts.TileSize
+}
+
+func writeSVGHeader(w io.Writer, width, height int, opts SVGOptions) {
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(w, `<defs><marker id="arrow" viewBox="0 0 10 10" refX="5" refY="5" markerWidth="4" markerHeight="4" orient="auto-start-reverse">`+
+		`<path d="M 0 0 L 10 5 L 0 10 z" fill="%s"/></marker></defs>`+"\n", escapeXML(opts.ArrowColor))
+}
+
+// writeBoard draws p with its top-left corner at (x, y). If move is not nil,
+// the tile it moves is highlighted and marked with an arrow.
+func writeBoard(w io.Writer, p Puzzle, x, y int, move *Move, opts SVGOptions) {
+	size := opts.TileSize
+	width, height := boardSize(p, opts)
+	fmt.Fprintf(w, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+		x, y, width, height, escapeXML(opts.BackgroundColor))
+
+	var moving coord
+	if move != nil {
+		moving = move.source(p.emptyTile.coord)
+	}
+
+	inset := max(1, size/20)
+	for row := range p.grid {
+		for col := range p.grid[row] {
+			tx := x + col*size + inset
+			ty := y + row*size + inset
+			here := coord{row: row, col: col}
+			if p.grid[row][col] == Blocked {
+				// Leave the background showing through.
+				continue
+			}
+
+			fill := opts.TileColor
+			switch {
+			case p.isEmpty(p.grid[row][col]):
+				fill = opts.EmptyColor
+			case move != nil && here == moving:
+				fill = opts.HighlightColor
+			}
+			fmt.Fprintf(w, `<rect x="%d" y="%d" width="%d" height="%d" rx="%d" fill="%s"/>`+"\n",
+				tx, ty, size-2*inset, size-2*inset, size/10, escapeXML(fill))
+
+			if p.isEmpty(p.grid[row][col]) {
+				continue
+			}
+			fmt.Fprintf(w, `<text x="%d" y="%d" font-family="sans-serif" font-size="%d" text-anchor="middle" dominant-baseline="central" fill="%s">%d</text>`+"\n",
+				x+col*size+size/2, y+row*size+size/2, size*2/5, escapeXML(opts.TextColor), p.color(p.grid[row][col]))
+		}
+	}
+
+	if move != nil {
+		// Draw the arrow from the centre of the moving tile towards the centre
+		// of the empty space, or the other way for BlankMoves.
+		from, to := moving, p.emptyTile.coord
+		if opts.Convention == BlankMoves {
+			from, to = to, from
+		}
+		x1 := x + from.col*size + size/2
+		y1 := y + from.row*size + size/2
+		x2 := x + to.col*size + size/2
+		y2 := y + to.row*size + size/2
+		fmt.Fprintf(w, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="%d" marker-end="url(#arrow)"/>`+"\n",
+			x1, y1, x2, y2, escapeXML(opts.ArrowColor), max(1, size/16))
+	}
+}
+
+// escapeXML escapes s for use as text or inside a double-quoted attribute.
+func escapeXML(s string) string {
+	var b strings.Builder
+	// Writing to a strings.Builder never fails.
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,109 @@
+package slide_puzzle
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// countElements parses an SVG document and counts its elements by name.
+func countElements(t *testing.T, doc []byte) map[string]int {
+	t.Helper()
+	counts := make(map[string]int)
+	decoder := xml.NewDecoder(bytes.NewReader(doc))
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			return counts
+		}
+		if err != nil {
+			t.Fatalf("SVG is not well-formed: %v\n%s", err, doc)
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			counts[start.Name.Local]++
+		}
+	}
+}
+
+func TestWritePuzzleSVG(t *testing.T) {
+	grid := [][]int{
+		{1, 2, 3},
+		{4, 0, 5},
+	}
+	puzzle, err := NewPuzzle(grid, 0)
+	if err != nil {
+		t.Fatalf("NewPuzzle() error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	opts := SVGOptions{TileSize: 10, TileColor: "plum"}
+	if err := WritePuzzleSVG(&buf, *puzzle, opts); err != nil {
+		t.Fatalf("WritePuzzleSVG() error: %v", err)
+	}
+
+	counts := countElements(t, buf.Bytes())
+	// One background plus one rect per cell.
+	if counts["rect"] != 7 {
+		t.Errorf("got %d rect elements, want 7", counts["rect"])
+	}
+	// The empty tile is not labelled.
+	if counts["text"] != 5 {
+		t.Errorf("got %d text elements, want 5", counts["text"])
+	}
+	if counts["line"] != 0 {
+		t.Errorf("got %d arrows, want 0", counts["line"])
+	}
+	if !strings.Contains(buf.String(), `width="30" height="20"`) {
+		t.Errorf("SVG does not have the expected size:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), `fill="plum"`) {
+		t.Errorf("SVG does not use the configured tile color:\n%s", buf.String())
+	}
+}
+
+func TestWriteSolutionSVG(t *testing.T) {
+	grid := [][]int{
+		{1, 2},
+		{0, 3},
+	}
+	puzzle, err := NewPuzzle(grid, 0)
+	if err != nil {
+		t.Fatalf("NewPuzzle() error: %v", err)
+	}
+
+	t.Run("one board per state with arrows", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteSolutionSVG(&buf, *puzzle, []Move{West, South}, DefaultSVGOptions()); err != nil {
+			t.Fatalf("WriteSolutionSVG() error: %v", err)
+		}
+
+		counts := countElements(t, buf.Bytes())
+		if counts["line"] != 2 {
+			t.Errorf("got %d arrows, want 2", counts["line"])
+		}
+		// Three boards of five rects each.
+		if counts["rect"] != 15 {
+			t.Errorf("got %d rect elements, want 15", counts["rect"])
+		}
+		for _, caption := range []string{"1. West", "2. South", "Solved"} {
+			if !strings.Contains(buf.String(), caption) {
+				t.Errorf("SVG is missing caption %q", caption)
+			}
+		}
+	})
+
+	t.Run("invalid move returns error", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := WriteSolutionSVG(&buf, *puzzle, []Move{East}, DefaultSVGOptions())
+		var invalidErr *InvalidMoveError
+		if !errors.As(err, &invalidErr) {
+			t.Fatalf("WriteSolutionSVG() error type = %T, want *InvalidMoveError", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("WriteSolutionSVG() wrote %d bytes on error, want 0", buf.Len())
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package slide_puzzle
+
+import "slices"
+
+// transposed returns the reflection of c about the main diagonal.
+func (c coord) transposed() coord {
+	return coord{row: c.col, col: c.row}
+}
+
+// index returns the row-major index of c on a board with the given number of
+// columns.
+func (c coord) index(cols int) int {
+	return c.row*cols + c.col
+}
+
+// hasDiagonalSymmetry reports whether transposing p's goal gives the same
+// goal, so that transposing p gives an equivalent puzzle. That is the case for
+// square boards whose empty tile belongs on the main diagonal.
+func (p Puzzle) hasDiagonalSymmetry() bool {
+	return p.preservesGoal(Transpose)
+}
+
+// preservesGoal reports whether t maps p's goal to itself, so that p and
+// p.Transform(t) are different instances of the same puzzle.
+func (p Puzzle) preservesGoal(t Transform) bool {
+	rows, cols := len(p.grid), len(p.grid[0])
+	newRows, newCols := t.dims(rows, cols)
+	if newRows != rows || newCols != cols {
+		return false
+	}
+	for _, val := range p.EmptyValues() {
+		if !p.isEmpty(t.value(val, rows, cols)) {
+			return false
+		}
+	}
+	for row := range p.grid {
+		for col, val := range p.grid[row] {
+			c := t.coord(coord{row: row, col: col}, rows, cols)
+			if val == Blocked && p.grid[c.row][c.col] != Blocked {
+				return false
+			}
+			if goal := row*cols + col; p.colors != nil && p.goalColor(goal) != p.goalColor(t.value(goal, rows, cols)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Canonical returns a representative of the puzzles equivalent to p by
+// symmetry: those reached by any combination of transforms that leave the
+// goal unchanged, such as transposing a square board whose empty tile belongs
+// on the main diagonal. Of these, the one with the smallest values (or colors,
+// for colored tiles) in row-major order is returned. Generators can use this to
+// avoid producing both a puzzle and its mirror image.
+func (p Puzzle) Canonical() Puzzle {
+	var symmetries []Transform
+	for _, t := range allTransforms {
+		if t != Identity && p.preservesGoal(t) {
+			symmetries = append(symmetries, t)
+		}
+	}
+
+	// Combining transforms can give others not in the list, such as quarter
+	// turns, so visit everything reachable.
+	best := p
+	seen := map[string]bool{p.String(): true}
+	queue := []Puzzle{p}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if slices.Compare(current.colorValues(), best.colorValues()) < 0 {
+			best = current
+		}
+		for _, t := range symmetries {
+			next := current.Transform(t)
+			if key := next.String(); !seen[key] {
+				seen[key] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return best
+}
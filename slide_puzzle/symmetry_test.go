@@ -0,0 +1,61 @@
+package slide_puzzle
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	t.Run("puzzle and its transpose share a canonical form", func(t *testing.T) {
+		rng := rand.New(rand.NewPCG(1, 1))
+		for range 20 {
+			p, err := Scramble(3, 3, 4, 30, rng)
+			if err != nil {
+				t.Fatalf("Scramble() error: %v", err)
+			}
+			reflected := p.Transform(Transpose)
+			a, b := p.Canonical(), reflected.Canonical()
+			assertPuzzlesEqual(t, &a, &b)
+
+			// The reflection is a valid puzzle with a solution of the same length.
+			if _, err := NewPuzzle(reflected.grid, 4); err != nil {
+				t.Fatalf("Transform(Transpose) produced an invalid grid %v: %v", reflected.grid, err)
+			}
+			want, err := p.SolveIDAStar(1)
+			if err != nil {
+				t.Fatalf("SolveIDAStar() error: %v", err)
+			}
+			got, err := reflected.SolveIDAStar(1)
+			if err != nil {
+				t.Fatalf("SolveIDAStar() error: %v", err)
+			}
+			if len(got) != len(want) {
+				t.Errorf("reflection of %v solved in %d moves, want %d", p, len(got), len(want))
+			}
+		}
+	})
+
+	t.Run("asymmetric goals are unchanged", func(t *testing.T) {
+		p, err := NewPuzzle([][]int{{1, 0, 2}, {3, 4, 5}}, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		got := p.Canonical()
+		assertPuzzlesEqual(t, p, &got)
+	})
+}
+
+func TestCanonicalAllSymmetries(t *testing.T) {
+	// With the empty tile in the center, all eight symmetries of the square
+	// keep the goal, including the quarter turns made by combining transforms.
+	p, err := Scramble(3, 3, 4, 30, rand.New(rand.NewPCG(2, 2)))
+	if err != nil {
+		t.Fatalf("Scramble() error: %v", err)
+	}
+	want := p.Canonical()
+	quarterTurn := p.Transform(Transpose).Transform(ReflectHorizontal)
+	for _, q := range []Puzzle{quarterTurn, quarterTurn.Transform(Rotate180), p.Transform(ReflectVertical)} {
+		got := q.Canonical()
+		assertPuzzlesEqual(t, &want, &got)
+	}
+}
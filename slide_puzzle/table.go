@@ -0,0 +1,345 @@
+package slide_puzzle
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+// maxTableCells is the largest board a LookupTable supports. A 2x6 or 3x4
+// board has 12!/2 reachable states, which take 120 MB at 4 bits each; the next
+// size up, 2x7, would take 22 GB.
+const maxTableCells = 12
+
+// tableUnreachable marks states that cannot be reached from the goal.
+const tableUnreachable = 0xf
+
+// tableModulus is the modulus distances are stored with, so that every value
+// except tableUnreachable fits in 4 bits.
+const tableModulus = 15
+
+// tableMagic starts every lookup table file, followed by a format version.
+var tableMagic = []byte("SPLT")
+
+const tableVersion = 2
+
+// InvalidTableError is returned when reading a lookup table that is not in the
+// expected format.
+type InvalidTableError struct {
+	msg string
+}
+
+func (e InvalidTableError) Error() string {
+	return e.msg
+}
+
+// LookupTable holds the distance to the goal of every state of a small board,
+// so puzzles of that size can be solved optimally without searching.
+//
+// Only the half of the states reachable from the goal are stored, each
+// indexed as described by rankState, and each takes 4 bits, holding its
+// distance modulo 15. That is enough to find an
+// optimal solution: every move changes the distance by exactly one, so some
+// neighbor of any unsolved state is one move closer to the goal, and it is the
+// only kind of neighbor whose stored value is one less modulo 15.
+type LookupTable struct {
+	rows, cols int
+	emptyValue int
+	data       []byte
+}
+
+// NewLookupTable computes the lookup table for boards of the given size and
+// empty tile by breadth-first search backwards from the goal. Boards may have
+// at most 12 cells, such as 3x4 or 2x6.
+func NewLookupTable(rows, cols, emptyTileValue int) (*LookupTable, error) {
+	if rows*cols > maxTableCells {
+		return nil, &InvalidPuzzleError{fmt.Sprintf(
+			"lookup tables support boards with at most %d cells; got %dx%d", maxTableCells, rows, cols,
+		)}
+	}
+	goal, err := Goal(rows, cols, emptyTileValue)
+	if err != nil {
+		return nil, err
+	}
+
+	t := newLookupTable(rows, cols, emptyTileValue)
+	board := newFlatPuzzle(*goal)
+	tiles := make([]int, rows*cols-1)
+	// Each layer of the search is a bitset over the states, rather than a
+	// queue, which on the largest boards would take several times as much
+	// memory as the table itself.
+	layer := make([]uint64, (2*len(t.data)+63)/64)
+	next := make([]uint64, len(layer))
+	root := rankState(board, tiles)
+	t.set(root, 0)
+	layer[root/64] |= 1 << (root % 64)
+	for distance := 0; ; distance++ {
+		found := false
+		for i, word := range layer {
+			for ; word != 0; word &= word - 1 {
+				unrankState(i*64+bits.TrailingZeros64(word), board, tiles)
+				for _, m := range allMoves {
+					src := board.source(m)
+					if src < 0 {
+						continue
+					}
+					empty := board.empty
+					board.apply(src)
+					if index := rankState(board, tiles); t.get(index) == tableUnreachable {
+						t.set(index, (distance+1)%tableModulus)
+						next[index/64] |= 1 << (index % 64)
+						found = true
+					}
+					board.apply(empty)
+				}
+			}
+		}
+		if !found {
+			return t, nil
+		}
+		layer, next = next, layer
+		clear(next)
+	}
+}
+
+func newLookupTable(rows, cols, emptyTileValue int) *LookupTable {
+	data := make([]byte, (rows*cols*tileOrders(rows*cols)+1)/2)
+	for i := range data {
+		data[i] = tableUnreachable<<4 | tableUnreachable
+	}
+	return &LookupTable{rows: rows, cols: cols, emptyValue: emptyTileValue, data: data}
+}
+
+func (t *LookupTable) get(rank int) int {
+	return int(t.data[rank/2]>>(4*(rank%2))) & 0xf
+}
+
+func (t *LookupTable) set(rank, value int) {
+	shift := 4 * (rank % 2)
+	t.data[rank/2] = t.data[rank/2]&^(0xf<<shift) | byte(value)<<shift
+}
+
+// Solve returns an optimal solution for p, which must have the same size and
+// empty tile as the table, by repeatedly moving to a neighbor one move closer
+// to the goal.
+func (t *LookupTable) Solve(p Puzzle) ([]Move, error) {
+	if err := p.requireSingleEmpty(); err != nil {
+		return nil, err
+	}
+	if err := p.requireDistinct(); err != nil {
+		return nil, err
+	}
+	if err := p.requireNoBlocked(); err != nil {
+		return nil, err
+	}
+	if len(p.grid) != t.rows || len(p.grid[0]) != t.cols || p.emptyTile.value != t.emptyValue {
+		return nil, &InvalidPuzzleError{fmt.Sprintf(
+			"lookup table is for %dx%d puzzles with empty tile %d; got %dx%d with empty tile %d",
+			t.rows, t.cols, t.emptyValue, len(p.grid), len(p.grid[0]), p.emptyTile.value,
+		)}
+	}
+	// Unreachable states share their index with reachable ones, so they must
+	// be ruled out first.
+	if !p.isSolvable() {
+		return nil, UnsolvablePuzzleError{}
+	}
+
+	board := newFlatPuzzle(p)
+	tiles := make([]int, len(board.cells)-1)
+	distance := t.get(rankState(board, tiles))
+	if distance == tableUnreachable {
+		return nil, UnsolvablePuzzleError{}
+	}
+
+	moves := []Move{}
+	for board.h > 0 {
+		closer := (distance + tableModulus - 1) % tableModulus
+		found := false
+		for _, m := range allMoves {
+			src := board.source(m)
+			if src < 0 {
+				continue
+			}
+			empty := board.empty
+			board.apply(src)
+			if t.get(rankState(board, tiles)) == closer {
+				moves = append(moves, m)
+				distance, found = closer, true
+				break
+			}
+			board.apply(empty)
+		}
+		if !found {
+			return nil, &InvalidTableError{"lookup table is inconsistent: no move leads closer to the goal"}
+		}
+	}
+	return moves, nil
+}
+
+// WriteTo writes the table to w. The format is the magic bytes "SPLT", a
+// version byte, the number of rows, columns and the empty tile value as one
+// byte each, and then the packed distances.
+func (t *LookupTable) WriteTo(w io.Writer) (int64, error) {
+	header := append(bytes.Clone(tableMagic), tableVersion, byte(t.rows), byte(t.cols), byte(t.emptyValue))
+	n, err := w.Write(header)
+	if err != nil {
+		return int64(n), err
+	}
+	m, err := w.Write(t.data)
+	return int64(n + m), err
+}
+
+// ReadLookupTable reads a table written by LookupTable.WriteTo.
+func ReadLookupTable(r io.Reader) (*LookupTable, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(tableMagic)+4)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, &InvalidTableError{fmt.Sprintf("reading lookup table header: %v", err)}
+	}
+	if !bytes.Equal(header[:len(tableMagic)], tableMagic) {
+		return nil, &InvalidTableError{"not a lookup table file"}
+	}
+	version, rows, cols, empty := header[4], int(header[5]), int(header[6]), int(header[7])
+	if version != tableVersion {
+		return nil, &InvalidTableError{fmt.Sprintf("unsupported lookup table version %d", version)}
+	}
+	if rows <= 0 || cols <= 0 || rows*cols > maxTableCells || empty >= rows*cols {
+		return nil, &InvalidTableError{fmt.Sprintf("invalid lookup table for %dx%d puzzles with empty tile %d", rows, cols, empty)}
+	}
+
+	t := newLookupTable(rows, cols, empty)
+	if _, err := io.ReadFull(br, t.data); err != nil {
+		return nil, &InvalidTableError{fmt.Sprintf("reading lookup table data: %v", err)}
+	}
+	if _, err := br.ReadByte(); err != io.EOF {
+		return nil, &InvalidTableError{"unexpected data after lookup table"}
+	}
+	return t, nil
+}
+
+// Size returns the number of rows and columns of the puzzles the table
+// solves, and their empty tile value.
+func (t *LookupTable) Size() (rows, cols, emptyTileValue int) {
+	return t.rows, t.cols, t.emptyValue
+}
+
+// rankState returns the index in a table of the state of board, which has no
+// blocked cells, among the states reachable from its goal. The index is the
+// position of the empty tile times tileOrders, plus half the rank of the order
+// of the other tiles, read in row-major order.
+//
+// With the empty tile in a given position, only one parity of that order is
+// reachable: sliding a tile sideways does not change the order, and sliding
+// one up or down moves it past cols-1 others. Orders that differ only by
+// swapping their last two tiles have opposite parities and ranks 2k and
+// 2k+1, so the two can share an index. tiles is scratch space for the order,
+// of length one less than the number of cells.
+func rankState(board *flatPuzzle, tiles []int) int {
+	tiles = tiles[:0]
+	for _, v := range board.cells {
+		// Number the tiles other than the empty one from 0.
+		switch {
+		case v < board.emptyValue:
+			tiles = append(tiles, v)
+		case v > board.emptyValue:
+			tiles = append(tiles, v-1)
+		}
+	}
+	return board.empty*tileOrders(len(board.cells)) + rankPermutation(tiles)/2
+}
+
+// unrankState sets board to the reachable state with the given index, as
+// returned by rankState. The Manhattan distance of board is not updated.
+func unrankState(index int, board *flatPuzzle, tiles []int) {
+	orders := tileOrders(len(board.cells))
+	board.empty = index / orders
+	unrankPermutation(2*(index%orders), tiles)
+
+	// The goal order is sorted, so the reachable parity is the number of
+	// rows the empty tile is from its goal position times cols-1.
+	want := abs(board.empty/board.cols-board.emptyValue/board.cols) * (board.cols - 1) % 2
+	inversions := 0
+	for i, v := range tiles {
+		for _, w := range tiles[i+1:] {
+			if w < v {
+				inversions++
+			}
+		}
+	}
+	if n := len(tiles); inversions%2 != want && n >= 2 {
+		tiles[n-2], tiles[n-1] = tiles[n-1], tiles[n-2]
+	}
+
+	next := 0
+	for i := range board.cells {
+		if i == board.empty {
+			board.cells[i] = board.emptyValue
+			continue
+		}
+		v := tiles[next]
+		next++
+		if v >= board.emptyValue {
+			v++
+		}
+		board.cells[i] = v
+	}
+}
+
+// tileOrders returns the number of table indexes for each position of the
+// empty tile on a board with the given number of cells: half the number of
+// orders of the other tiles, rounded up.
+func tileOrders(cells int) int {
+	return (factorial(cells-1) + 1) / 2
+}
+
+// rankPermutation returns the position of perm, a permutation of 0..n-1, in
+// the lexicographic order of all such permutations.
+func rankPermutation(perm []int) int {
+	rank := 0
+	for i, v := range perm {
+		smaller := 0
+		for _, w := range perm[i+1:] {
+			if w < v {
+				smaller++
+			}
+		}
+		rank = rank*(len(perm)-i) + smaller
+	}
+	return rank
+}
+
+// unrankPermutation fills perm with the permutation of 0..len(perm)-1 with the
+// given rank.
+func unrankPermutation(rank int, perm []int) {
+	n := len(perm)
+	// Recover the count of smaller values after each position, last first.
+	for i := n - 1; i >= 0; i-- {
+		perm[i] = rank % (n - i)
+		rank /= n - i
+	}
+	// Turn the counts into values by picking from those not yet used.
+	var used [maxTableCells]bool
+	for i, smaller := range perm {
+		for v := range n {
+			if used[v] {
+				continue
+			}
+			if smaller == 0 {
+				perm[i] = v
+				used[v] = true
+				break
+			}
+			smaller--
+		}
+	}
+}
+
+func factorial(n int) int {
+	f := 1
+	for i := 2; i <= n; i++ {
+		f *= i
+	}
+	return f
+}
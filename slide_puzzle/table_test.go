@@ -0,0 +1,202 @@
+package slide_puzzle
+
+import (
+	"bytes"
+	"errors"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRankPermutation(t *testing.T) {
+	perm := make([]int, 5)
+	for rank := range factorial(5) {
+		unrankPermutation(rank, perm)
+		if got := rankPermutation(perm); got != rank {
+			t.Fatalf("rankPermutation(%v) = %d, want %d", perm, got, rank)
+		}
+	}
+	if diff := cmp.Diff([]int{4, 3, 2, 1, 0}, perm); diff != "" {
+		t.Errorf("last permutation mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRankState(t *testing.T) {
+	// Every state reachable from the goal has its own index, and unranking
+	// the index gives the state back.
+	for _, size := range [][3]int{{2, 3, 0}, {3, 2, 4}, {3, 3, 8}, {1, 4, 2}} {
+		rows, cols, empty := size[0], size[1], size[2]
+		goal, err := Goal(rows, cols, empty)
+		if err != nil {
+			t.Fatalf("Goal() error: %v", err)
+		}
+		tiles := make([]int, rows*cols-1)
+		indexes := map[int]Puzzle{}
+		visited := map[string]bool{goal.String(): true}
+		for queue := []Puzzle{*goal}; len(queue) > 0; queue = queue[1:] {
+			p := queue[0]
+			board := newFlatPuzzle(p)
+			index := rankState(board, tiles)
+			if index < 0 || index >= rows*cols*tileOrders(rows*cols) {
+				t.Fatalf("rankState(%v) = %d, out of range", p, index)
+			}
+			if other, ok := indexes[index]; ok {
+				t.Fatalf("rankState() = %d for both %v and %v", index, other, p)
+			}
+			indexes[index] = p
+			unrankState(index, board, tiles)
+			if got := board.puzzle(); !got.Equal(p) {
+				t.Fatalf("unrankState(%d) = %v, want %v", index, got, p)
+			}
+
+			for m := range p.getMoves() {
+				next, err := p.makeMove(m)
+				if err != nil {
+					t.Fatalf("makeMove() error: %v", err)
+				}
+				if !visited[next.String()] {
+					visited[next.String()] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+}
+
+func TestLookupTable(t *testing.T) {
+	table, err := NewLookupTable(2, 4, 3)
+	if err != nil {
+		t.Fatalf("NewLookupTable() error: %v", err)
+	}
+
+	t.Run("solutions are optimal", func(t *testing.T) {
+		rng := rand.New(rand.NewPCG(3, 4))
+		for range 20 {
+			p, err := Scramble(2, 4, 3, 40, rng)
+			if err != nil {
+				t.Fatalf("Scramble() error: %v", err)
+			}
+			want, err := p.Solve()
+			if err != nil {
+				t.Fatalf("Solve() error: %v", err)
+			}
+			got, err := table.Solve(*p)
+			if err != nil {
+				t.Fatalf("LookupTable.Solve() error: %v", err)
+			}
+			assertSolves(t, *p, got)
+			if len(got) != len(want) {
+				t.Errorf("LookupTable.Solve(%v) took %d moves, want %d", p, len(got), len(want))
+			}
+		}
+	})
+
+	t.Run("solved puzzle needs no moves", func(t *testing.T) {
+		goal, err := Goal(2, 4, 3)
+		if err != nil {
+			t.Fatalf("Goal() error: %v", err)
+		}
+		moves, err := table.Solve(*goal)
+		if err != nil {
+			t.Fatalf("LookupTable.Solve() error: %v", err)
+		}
+		if len(moves) != 0 {
+			t.Errorf("LookupTable.Solve() = %v, want no moves", moves)
+		}
+	})
+
+	t.Run("unsolvable puzzle returns error", func(t *testing.T) {
+		p, err := NewPuzzle([][]int{{1, 0, 2, 3}, {4, 5, 6, 7}}, 3)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		_, err = table.Solve(*p)
+		if !errors.As(err, &UnsolvablePuzzleError{}) {
+			t.Errorf("LookupTable.Solve() error = %v, want UnsolvablePuzzleError", err)
+		}
+	})
+
+	t.Run("wrong size returns error", func(t *testing.T) {
+		p, err := Goal(2, 3, 3)
+		if err != nil {
+			t.Fatalf("Goal() error: %v", err)
+		}
+		_, err = table.Solve(*p)
+		var invalidErr *InvalidPuzzleError
+		if !errors.As(err, &invalidErr) {
+			t.Errorf("LookupTable.Solve() error type = %T, want *InvalidPuzzleError", err)
+		}
+	})
+
+	t.Run("round trips through a file", func(t *testing.T) {
+		var buf bytes.Buffer
+		if _, err := table.WriteTo(&buf); err != nil {
+			t.Fatalf("WriteTo() error: %v", err)
+		}
+		read, err := ReadLookupTable(&buf)
+		if err != nil {
+			t.Fatalf("ReadLookupTable() error: %v", err)
+		}
+		if diff := cmp.Diff(table, read, cmp.AllowUnexported(LookupTable{})); diff != "" {
+			t.Errorf("ReadLookupTable() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("invalid files return error", func(t *testing.T) {
+		var buf bytes.Buffer
+		if _, err := table.WriteTo(&buf); err != nil {
+			t.Fatalf("WriteTo() error: %v", err)
+		}
+		valid := buf.Bytes()
+
+		for name, data := range map[string][]byte{
+			"empty":          nil,
+			"bad magic":      append([]byte("XXXX"), valid[4:]...),
+			"bad version":    append(append([]byte("SPLT"), 9), valid[5:]...),
+			"too large":      append(append([]byte("SPLT"), tableVersion, 2, 7, 0), valid[8:]...),
+			"truncated":      valid[:len(valid)-1],
+			"trailing bytes": append(bytes.Clone(valid), 0),
+		} {
+			_, err := ReadLookupTable(bytes.NewReader(data))
+			var tableErr *InvalidTableError
+			if !errors.As(err, &tableErr) {
+				t.Errorf("ReadLookupTable(%s) error = %v, want *InvalidTableError", name, err)
+			}
+		}
+	})
+
+	t.Run("odd number of columns", func(t *testing.T) {
+		table, err := NewLookupTable(3, 3, 0)
+		if err != nil {
+			t.Fatalf("NewLookupTable() error: %v", err)
+		}
+		rng := rand.New(rand.NewPCG(5, 6))
+		for range 5 {
+			p, err := Scramble(3, 3, 0, 40, rng)
+			if err != nil {
+				t.Fatalf("Scramble() error: %v", err)
+			}
+			want, err := p.SolveIDAStar(0)
+			if err != nil {
+				t.Fatalf("SolveIDAStar() error: %v", err)
+			}
+			got, err := table.Solve(*p)
+			if err != nil {
+				t.Fatalf("LookupTable.Solve() error: %v", err)
+			}
+			assertSolves(t, *p, got)
+			if len(got) != len(want) {
+				t.Errorf("LookupTable.Solve(%v) took %d moves, want %d", p, len(got), len(want))
+			}
+		}
+	})
+
+	t.Run("large boards return error", func(t *testing.T) {
+		_, err := NewLookupTable(2, 7, 0)
+		var invalidErr *InvalidPuzzleError
+		if !errors.As(err, &invalidErr) {
+			t.Errorf("NewLookupTable() error type = %T, want *InvalidPuzzleError", err)
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package slide_puzzle
+
+// Transform is a rearrangement of a board that maps puzzles to equivalent
+// puzzles: the transformed puzzle is solved by the transformed solution.
+// Every transform is its own inverse.
+type Transform int
+
+const (
+	// Identity leaves the board unchanged.
+	Identity Transform = iota
+	// Transpose reflects the board about its main diagonal, turning an RxC
+	// board into a CxR one.
+	Transpose
+	// ReflectHorizontal mirrors the board left to right.
+	ReflectHorizontal
+	// ReflectVertical mirrors the board top to bottom.
+	ReflectVertical
+	// Rotate180 turns the board half way around.
+	Rotate180
+)
+
+// allTransforms lists every transform.
+var allTransforms = []Transform{Identity, Transpose, ReflectHorizontal, ReflectVertical, Rotate180}
+
+var transformStrings = map[Transform]string{
+	Identity:          "Identity",
+	Transpose:         "Transpose",
+	ReflectHorizontal: "ReflectHorizontal",
+	ReflectVertical:   "ReflectVertical",
+	Rotate180:         "Rotate180",
+}
+
+func (t Transform) String() string {
+	return transformStrings[t]
+}
+
+// dims returns the dimensions of a rows x cols board after the transform.
+func (t Transform) dims(rows, cols int) (int, int) {
+	if t == Transpose {
+		return cols, rows
+	}
+	return rows, cols
+}
+
+// coord returns where the transform takes position c on a rows x cols board.
+func (t Transform) coord(c coord, rows, cols int) coord {
+	switch t {
+	case Transpose:
+		return c.transposed()
+	case ReflectHorizontal:
+		return coord{row: c.row, col: cols - 1 - c.col}
+	case ReflectVertical:
+		return coord{row: rows - 1 - c.row, col: c.col}
+	case Rotate180:
+		return coord{row: rows - 1 - c.row, col: cols - 1 - c.col}
+	}
+	return c
+}
+
+// value returns the tile value that val becomes on a rows x cols board: the
+// value whose goal position is where the transform takes val's goal position.
+func (t Transform) value(val, rows, cols int) int {
+	_, newCols := t.dims(rows, cols)
+	return t.coord(coord{row: val / cols, col: val % cols}, rows, cols).index(newCols)
+}
+
+// Move returns the move that corresponds to m on the transformed board.
+func (t Transform) Move(m Move) Move {
+	switch t {
+	case Transpose:
+		switch m {
+		case North:
+			return West
+		case West:
+			return North
+		case South:
+			return East
+		default:
+			return South
+		}
+	case ReflectHorizontal:
+		if m == East || m == West {
+			return m.opposite()
+		}
+	case ReflectVertical:
+		if m == North || m == South {
+			return m.opposite()
+		}
+	case Rotate180:
+		return m.opposite()
+	}
+	return m
+}
+
+// Moves returns moves mapped through the transform, so that a solution to a
+// puzzle becomes a solution to the transformed puzzle.
+func (t Transform) Moves(moves []Move) []Move {
+	mapped := make([]Move, len(moves))
+	for i, m := range moves {
+		mapped[i] = t.Move(m)
+	}
+	return mapped
+}
+
+// Transform returns p rearranged by t. Tiles are relabeled relative to the
+// goal, so that the transformed goal state is the goal state of the new board:
+// each tile becomes the value whose goal position is where the tile's own goal
+// position is taken. This can change the values of the empty tiles.
+func (p Puzzle) Transform(t Transform) Puzzle {
+	rows, cols := len(p.grid), len(p.grid[0])
+	newRows, newCols := t.dims(rows, cols)
+
+	grid := make([][]int, newRows)
+	for row := range grid {
+		grid[row] = make([]int, newCols)
+	}
+	for row := range p.grid {
+		for col, val := range p.grid[row] {
+			c := t.coord(coord{row: row, col: col}, rows, cols)
+			if val != Blocked {
+				val = t.value(val, rows, cols)
+			}
+			grid[c.row][c.col] = val
+		}
+	}
+	move := func(empty tile) tile {
+		return tile{
+			value: t.value(empty.value, rows, cols),
+			coord: t.coord(empty.coord, rows, cols),
+		}
+	}
+	transformed := Puzzle{grid: grid, emptyTile: move(p.emptyTile)}
+	if p.colors != nil {
+		transformed.colors = make([]int, len(p.colors))
+		for val, color := range p.colors {
+			transformed.colors[t.value(val, rows, cols)] = color
+		}
+	}
+	if p.wildcards != nil {
+		transformed.wildcards = make([]bool, len(p.wildcards))
+		for cell, wild := range p.wildcards {
+			transformed.wildcards[t.value(cell, rows, cols)] = wild
+		}
+	}
+	for _, h := range p.holes {
+		transformed.holes = append(transformed.holes, move(h))
+	}
+	return transformed
+}
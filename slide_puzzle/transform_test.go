@@ -0,0 +1,66 @@
+package slide_puzzle
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTransform(t *testing.T) {
+	t.Run("transpose example", func(t *testing.T) {
+		p, err := NewPuzzle([][]int{{1, 0, 2}, {3, 4, 5}}, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		// Goal positions on the 2x3 board map to 3x2 values as
+		// 0->0, 1->2, 2->4, 3->1, 4->3, 5->5.
+		want, err := NewPuzzle([][]int{{2, 1}, {0, 3}, {4, 5}}, 0)
+		if err != nil {
+			t.Fatalf("NewPuzzle() error: %v", err)
+		}
+		got := p.Transform(Transpose)
+		assertPuzzlesEqual(t, want, &got)
+	})
+
+	t.Run("reflection relabels the empty tile", func(t *testing.T) {
+		goal, err := Goal(3, 3, 0)
+		if err != nil {
+			t.Fatalf("Goal() error: %v", err)
+		}
+		got := goal.Transform(ReflectHorizontal)
+		want, err := Goal(3, 3, 2)
+		if err != nil {
+			t.Fatalf("Goal() error: %v", err)
+		}
+		assertPuzzlesEqual(t, want, &got)
+	})
+
+	rng := rand.New(rand.NewPCG(9, 9))
+	for _, size := range []struct{ rows, cols, empty int }{{2, 3, 0}, {3, 3, 4}, {3, 4, 7}} {
+		p, err := Scramble(size.rows, size.cols, size.empty, 30, rng)
+		if err != nil {
+			t.Fatalf("Scramble() error: %v", err)
+		}
+		moves, err := p.SolveIDAStar(1)
+		if err != nil {
+			t.Fatalf("SolveIDAStar() error: %v", err)
+		}
+
+		for _, tr := range allTransforms {
+			t.Run(tr.String(), func(t *testing.T) {
+				got := p.Transform(tr)
+				if _, err := NewPuzzle(got.grid, got.emptyTile.value); err != nil {
+					t.Fatalf("Transform(%v) produced an invalid puzzle %v: %v", tr, got, err)
+				}
+				assertSolves(t, got, tr.Moves(moves))
+
+				back := got.Transform(tr)
+				assertPuzzlesEqual(t, p, &back)
+				if diff := cmp.Diff(moves, tr.Moves(tr.Moves(moves))); diff != "" {
+					t.Errorf("Moves() twice mismatch (-want +got):\n%s", diff)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/kevin-hanselman/slide-puzzle-solver/slide_puzzle"
+)
+
+func runTable(args []string) error {
+	flags := flag.NewFlagSet("table", flag.ContinueOnError)
+	rows := flags.Int("rows", 0, "number of rows in the puzzle; boards may have at most 12 cells, such as 3x4 or 2x6")
+	cols := flags.Int("cols", 0, "number of columns in the puzzle; boards may have at most 12 cells, so 2x7 and larger are not supported")
+	empty := flags.Int("empty", 0, "value representing the empty tile")
+	output := flags.String("o", "", "file to write the lookup table to")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *rows <= 0 {
+		return fmt.Errorf("-rows must be positive")
+	}
+	if *cols <= 0 {
+		return fmt.Errorf("-cols must be positive")
+	}
+	if *output == "" {
+		return fmt.Errorf("-o is required")
+	}
+
+	table, err := slide_puzzle.NewLookupTable(*rows, *cols, *empty)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if _, err := table.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// loadTable reads a lookup table written by the table subcommand.
+func loadTable(path string) (*slide_puzzle.LookupTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return slide_puzzle.ReadLookupTable(f)
+}